@@ -18,648 +18,193 @@ limitations under the License.
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	kingpin "github.com/alecthomas/kingpin/v2"
 	"github.com/pkg/errors"
 	"golang.org/x/tools/go/packages"
 
-	"github.com/crossplane/crossplane-tools/internal/comments"
-	"github.com/crossplane/crossplane-tools/internal/generate"
-	"github.com/crossplane/crossplane-tools/internal/match"
-	"github.com/crossplane/crossplane-tools/internal/method"
-	"github.com/crossplane/crossplane-tools/internal/types"
+	"github.com/crossplane/crossplane-tools/pkg/angryjet"
 )
 
-const (
-	// LoadMode used to load all packages.
-	LoadMode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax
-
-	// DisableMarker used to disable generation of managed resource methods for
-	// a type that otherwise appears to be a managed resource that is missing a
-	// subnet of its methods.
-	DisableMarker = "crossplane:generate:methods"
-)
-
-// Imports used in generated code.
-const (
-	CoreAlias  = "corev1"
-	CoreImport = "k8s.io/api/core/v1"
-
-	ClientAlias  = "client"
-	ClientImport = "sigs.k8s.io/controller-runtime/pkg/client"
-
-	RuntimeAlias  = "xpv1"
-	RuntimeImport = "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
-
-	RuntimeV2Alias  = "xpv2"
-	RuntimeV2Import = "github.com/crossplane/crossplane/apis/v2/core/v2"
-
-	ResourceAlias  = "resource"
-	ResourceImport = "github.com/crossplane/crossplane-runtime/v2/pkg/resource"
-
-	ReferenceAlias  = "reference"
-	ReferenceImport = "github.com/crossplane/crossplane-runtime/v2/pkg/reference"
-)
+// A filename flag configures the filename of a generator output. Filenames
+// that aren't set by the user don't override the configuration file.
+type filename struct {
+	output string
+	value  *string
+	set    bool
+}
 
 func main() {
 	var (
 		app = kingpin.New(filepath.Base(os.Args[0]), "Generates Crossplane API type methods.").DefaultEnvars()
 
-		methodsets          = app.Command("generate-methodsets", "Generate a Crossplane method sets.")
-		headerFile          = methodsets.Flag("header-file", "The contents of this file will be added to the top of all generated files.").ExistingFile()
-		filenameManaged     = methodsets.Flag("filename-managed", "The filename of generated managed resource files.").Default("zz_generated.managed.go").String()
-		filenameResolvers   = methodsets.Flag("filename-resolvers", "The filename of generated reference resolver files.").Default("zz_generated.resolvers.go").String()
-		filenameManagedList = methodsets.Flag("filename-managed-list", "The filename of generated managed list resource files.").Default("zz_generated.managedlist.go").String()
-		filenamePC          = methodsets.Flag("filename-pc", "The filename of generated provider config files.").Default("zz_generated.pc.go").String()
-		filenamePCU         = methodsets.Flag("filename-pcu", "The filename of generated provider config usage files.").Default("zz_generated.pcu.go").String()
-		filenamePCUList     = methodsets.Flag("filename-pcu-list", "The filename of generated provider config usage files.").Default("zz_generated.pculist.go").String()
-		pattern             = methodsets.Arg("packages", "Package(s) for which to generate methods, for example github.com/crossplane/crossplane/apis/...").String()
-	)
-	kingpin.MustParse(app.Parse(os.Args[1:]))
-
-	pkgs, err := packages.Load(&packages.Config{Mode: LoadMode}, *pattern)
-	kingpin.FatalIfError(err, "cannot load packages %s", *pattern)
-
-	header := ""
-	if *headerFile != "" {
-		h, err := os.ReadFile(*headerFile)
-		kingpin.FatalIfError(err, "cannot read header file %s", *headerFile)
-		header = string(h)
-	}
-
-	for _, p := range pkgs {
-		for _, err := range p.Errors {
-			kingpin.FatalIfError(err, "error loading packages using pattern %s", *pattern)
+		methodsets = app.Command("generate-methodsets", "Generate a Crossplane method sets.")
+		configFile = methodsets.Flag("config", "Configuration file. Defaults to the "+angryjet.ConfigFilename+" at the root of the current module, if any.").ExistingFile()
+		headerFile = methodsets.Flag("header-file", "The contents of this file will be added to the top of all generated files.").ExistingFile()
+		filenames  = []*filename{
+			newFilename(methodsets, angryjet.OutputManaged, "filename-managed", "The filename of generated managed resource files."),
+			newFilename(methodsets, angryjet.OutputResolvers, "filename-resolvers", "The filename of generated reference resolver files."),
+			newFilename(methodsets, angryjet.OutputManagedList, "filename-managed-list", "The filename of generated managed list resource files."),
+			newFilename(methodsets, angryjet.OutputPC, "filename-pc", "The filename of generated provider config files."),
+			newFilename(methodsets, angryjet.OutputPCU, "filename-pcu", "The filename of generated provider config usage files."),
+			newFilename(methodsets, angryjet.OutputPCUList, "filename-pcu-list", "The filename of generated provider config usage files."),
+			newFilename(methodsets, angryjet.OutputComposite, "filename-composite", "The filename of generated composite resource and claim files."),
+			newFilename(methodsets, angryjet.OutputPackage, "filename-package", "The filename of generated package and package revision files."),
 		}
-		kingpin.FatalIfError(GenerateManagedLegacy(*filenameManaged, header, p), "cannot write managed resource method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateManagedModern(*filenameManaged, header, p), "cannot write managed resource method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateManagedLegacyCore(*filenameManaged, header, p), "cannot write core API managed resource method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateManagedModernCore(*filenameManaged, header, p), "cannot write core API managed resource method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateManagedListLegacy(*filenameManagedList, header, p), "cannot write managed resource list method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateManagedListModern(*filenameManagedList, header, p), "cannot write managed resource list method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateManagedListLegacyCore(*filenameManagedList, header, p), "cannot write core API managed resource list method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateManagedListModernCore(*filenameManagedList, header, p), "cannot write core API managed resource list method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateProviderConfig(*filenamePC, header, p), "cannot write provider config method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateProviderConfigCore(*filenamePC, header, p), "cannot write core API provider config method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateProviderConfigUsageLegacy(*filenamePCU, header, p), "cannot write provider config usage method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateProviderConfigUsageModern(*filenamePCU, header, p), "cannot write provider config usage method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateProviderConfigUsageLegacyCore(*filenamePCU, header, p), "cannot write core API provider config usage method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateProviderConfigUsageModernCore(*filenamePCU, header, p), "cannot write core API provider config usage method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateProviderConfigUsageListLegacy(*filenamePCUList, header, p), "cannot write provider config usage list method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateProviderConfigUsageListModern(*filenamePCUList, header, p), "cannot write provider config usage list method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateProviderConfigUsageListLegacyCore(*filenamePCUList, header, p), "cannot write core API provider config usage list method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateProviderConfigUsageListModernCore(*filenamePCUList, header, p), "cannot write core API provider config usage list method set for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateReferencesLegacy(*filenameResolvers, header, p), "cannot write reference resolvers for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateReferencesModern(*filenameResolvers, header, p), "cannot write reference resolvers for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateReferencesLegacyCore(*filenameResolvers, header, p), "cannot write core API reference resolvers for package %s", p.PkgPath)
-		kingpin.FatalIfError(GenerateReferencesModernCore(*filenameResolvers, header, p), "cannot write core API reference resolvers for package %s", p.PkgPath)
-	}
-}
-
-// GenerateManagedLegacy generates the resource.Managed method set.
-func GenerateManagedLegacy(filename, header string, p *packages.Package) error {
-	receiver := "mg"
-
-	methods := method.Set{
-		"SetConditions":                       method.NewSetConditions(receiver, RuntimeImport),
-		"GetCondition":                        method.NewGetCondition(receiver, RuntimeImport),
-		"GetProviderConfigReference":          method.NewGetProviderConfigReference(receiver, RuntimeImport),
-		"SetProviderConfigReference":          method.NewSetProviderConfigReference(receiver, RuntimeImport),
-		"SetWriteConnectionSecretToReference": method.NewSetWriteConnectionSecretToReference(receiver, RuntimeImport),
-		"GetWriteConnectionSecretToReference": method.NewGetWriteConnectionSecretToReference(receiver, RuntimeImport),
-		"SetManagementPolicies":               method.NewSetManagementPolicies(receiver, RuntimeImport),
-		"GetManagementPolicies":               method.NewGetManagementPolicies(receiver, RuntimeImport),
-		"SetDeletionPolicy":                   method.NewSetDeletionPolicy(receiver, RuntimeImport),
-		"GetDeletionPolicy":                   method.NewGetDeletionPolicy(receiver, RuntimeImport),
-	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{
-			CoreImport:    CoreAlias,
-			RuntimeImport: RuntimeAlias,
-		}),
-		generate.WithMatcher(match.AllOf(
-			match.ManagedLegacy(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write managed resource methods")
-}
-
-// GenerateManagedModern generates the resource.Managed method set for v2-style namespaced MRs.
-func GenerateManagedModern(filename, header string, p *packages.Package) error {
-	receiver := "mg"
-
-	methods := method.Set{
-		"SetConditions":                       method.NewSetConditions(receiver, RuntimeImport),
-		"GetCondition":                        method.NewGetCondition(receiver, RuntimeImport),
-		"GetProviderConfigReference":          method.NewGetTypedProviderConfigReference(receiver, RuntimeImport),
-		"SetProviderConfigReference":          method.NewSetTypedProviderConfigReference(receiver, RuntimeImport),
-		"SetWriteConnectionSecretToReference": method.NewLocalSetWriteConnectionSecretToReference(receiver, RuntimeImport),
-		"GetWriteConnectionSecretToReference": method.NewLocalGetWriteConnectionSecretToReference(receiver, RuntimeImport),
-		"SetManagementPolicies":               method.NewSetManagementPolicies(receiver, RuntimeImport),
-		"GetManagementPolicies":               method.NewGetManagementPolicies(receiver, RuntimeImport),
-	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{
-			CoreImport:    CoreAlias,
-			RuntimeImport: RuntimeAlias,
-		}),
-		generate.WithMatcher(match.AllOf(
-			match.ManagedModern(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write V2 managed resource methods")
-}
-
-// GenerateManagedModernCore generates the resource.Managed method set for
-// namespaced (modern) MRs using the crossplane core API v2 types.
-func GenerateManagedModernCore(filename, header string, p *packages.Package) error {
-	receiver := "mg"
-
-	methods := method.Set{
-		"SetConditions":                       method.NewSetConditions(receiver, RuntimeV2Import),
-		"GetCondition":                        method.NewGetCondition(receiver, RuntimeV2Import),
-		"GetProviderConfigReference":          method.NewGetTypedProviderConfigReference(receiver, RuntimeV2Import),
-		"SetProviderConfigReference":          method.NewSetTypedProviderConfigReference(receiver, RuntimeV2Import),
-		"SetWriteConnectionSecretToReference": method.NewLocalSetWriteConnectionSecretToReference(receiver, RuntimeV2Import),
-		"GetWriteConnectionSecretToReference": method.NewLocalGetWriteConnectionSecretToReference(receiver, RuntimeV2Import),
-		"SetManagementPolicies":               method.NewSetManagementPolicies(receiver, RuntimeV2Import),
-		"GetManagementPolicies":               method.NewGetManagementPolicies(receiver, RuntimeV2Import),
-	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{
-			CoreImport:      CoreAlias,
-			RuntimeV2Import: RuntimeV2Alias,
-		}),
-		generate.WithMatcher(match.AllOf(
-			match.ManagedModernCore(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write core API managed resource methods")
-}
-
-// GenerateManagedLegacyCore generates the resource.Managed method set for
-// cluster-scoped (legacy) MRs using the crossplane core API v2 types.
-func GenerateManagedLegacyCore(filename, header string, p *packages.Package) error {
-	receiver := "mg"
-
-	methods := method.Set{
-		"SetConditions":                       method.NewSetConditions(receiver, RuntimeV2Import),
-		"GetCondition":                        method.NewGetCondition(receiver, RuntimeV2Import),
-		"GetProviderConfigReference":          method.NewGetProviderConfigReference(receiver, RuntimeV2Import),
-		"SetProviderConfigReference":          method.NewSetProviderConfigReference(receiver, RuntimeV2Import),
-		"SetWriteConnectionSecretToReference": method.NewSetWriteConnectionSecretToReference(receiver, RuntimeV2Import),
-		"GetWriteConnectionSecretToReference": method.NewGetWriteConnectionSecretToReference(receiver, RuntimeV2Import),
-		"SetManagementPolicies":               method.NewSetManagementPolicies(receiver, RuntimeV2Import),
-		"GetManagementPolicies":               method.NewGetManagementPolicies(receiver, RuntimeV2Import),
-		"SetDeletionPolicy":                   method.NewSetDeletionPolicy(receiver, RuntimeV2Import),
-		"GetDeletionPolicy":                   method.NewGetDeletionPolicy(receiver, RuntimeV2Import),
-	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{
-			CoreImport:      CoreAlias,
-			RuntimeV2Import: RuntimeV2Alias,
-		}),
-		generate.WithMatcher(match.AllOf(
-			match.ManagedLegacyCore(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write core API cluster managed resource methods")
-}
-
-// GenerateManagedListLegacy generates the resource.ManagedList method set.
-func GenerateManagedListLegacy(filename, header string, p *packages.Package) error {
-	receiver := "l"
-
-	methods := method.Set{
-		"GetItems": method.NewManagedGetItems(receiver, ResourceImport),
+		dryRun        = methodsets.Flag("dry-run", "Print which files would be created, changed or deleted, without writing them.").Bool()
+		showDiff      = methodsets.Flag("diff", "Print a unified diff between existing and generated files, without writing them.").Bool()
+		check         = methodsets.Flag("check", "Exit non-zero if any generated file is missing or out of date, without writing them.").Bool()
+		jobs          = methodsets.Flag("jobs", "The number of packages to generate concurrently.").Short('j').Default("1").Int()
+		strictMarkers = methodsets.Flag("strict-markers", "Treat malformed crossplane:generate markers as errors rather than warnings.").Bool()
+		modulePaths   = methodsets.Flag("module-path", "Use the packages of another module in place of those of a module, for example github.com/crossplane/crossplane-runtime/v2=example.org/crossplane-runtime/v2. May be repeated.").PlaceHolder("MODULE=REPLACEMENT").StringMap()
+		filter        = methodsets.Flag("filter", "Only generate methods for types that match this expression, for example 'matches(\"managed-modern\") && !path(\"/deprecated/\")'.").PlaceHolder("EXPR").String()
+		pattern       = methodsets.Arg("packages", "Package(s) for which to generate methods, for example github.com/crossplane/crossplane/apis/...").String()
+
+		explain           = app.Command("explain", "Explain why types do or do not match each generator.")
+		explainConfigFile = explain.Flag("config", "Configuration file. Defaults to the "+angryjet.ConfigFilename+" at the root of the current module, if any.").ExistingFile()
+		explainPackage    = explain.Arg("package", "Package containing the types to explain, for example ./apis/v1.").Required().String()
+		explainType       = explain.Arg("type", "Type to explain. All types in the package are explained if omitted.").String()
+		explainModules    = explain.Flag("module-path", "Use the packages of another module in place of those of a module. May be repeated.").PlaceHolder("MODULE=REPLACEMENT").StringMap()
+		explainFilter     = explain.Flag("filter", "Explain as if only types that match this expression were generated.").PlaceHolder("EXPR").String()
+	)
+
+	if kingpin.MustParse(app.Parse(os.Args[1:])) == explain.FullCommand() {
+		_, opts := loadConfig(*explainConfigFile)
+		opts = append(opts, withModulePaths(*explainModules)...)
+		if *explainFilter != "" {
+			opts = append(opts, angryjet.WithFilter(*explainFilter))
+		}
+		es, err := angryjet.Explain(&packages.Config{}, *explainPackage, *explainType, opts...)
+		kingpin.FatalIfError(err, "cannot explain package %s", *explainPackage)
+		for _, e := range es {
+			fmt.Println(e)
+		}
+		return
 	}
 
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{
-			ResourceImport: ResourceAlias,
-		}),
-		generate.WithMatcher(match.AllOf(
-			match.ManagedListLegacy(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write managed resource list methods")
-}
-
-// GenerateManagedListModern generates the resource.ManagedList method set for v2-style namespaced MRs.
-func GenerateManagedListModern(filename, header string, p *packages.Package) error {
-	receiver := "l"
-
-	methods := method.Set{
-		"GetItems": method.NewManagedGetItems(receiver, ResourceImport),
+	severity := angryjet.SeverityWarning
+	if *strictMarkers {
+		severity = angryjet.SeverityError
 	}
 
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{
-			ResourceImport: ResourceAlias,
+	cfg := &packages.Config{}
+	patterns := []string{*pattern}
+	opts := []angryjet.Option{
+		angryjet.WithJobs(*jobs),
+		angryjet.WithMarkerSeverity(severity),
+		angryjet.WithWarnings(func(w error) {
+			fmt.Fprintf(os.Stderr, "%s: warning: %s\n", app.Name, angryjet.Format(w))
 		}),
-		generate.WithMatcher(match.AllOf(
-			match.ManagedListModern(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write V2 managed resource list methods")
-}
-
-// GenerateManagedListModernCore generates the resource.ManagedList method set
-// for namespaced (modern) MRs using the crossplane core API v2 types.
-func GenerateManagedListModernCore(filename, header string, p *packages.Package) error {
-	receiver := "l"
-
-	methods := method.Set{
-		"GetItems": method.NewManagedGetItems(receiver, ResourceImport),
 	}
 
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{
-			ResourceImport: ResourceAlias,
-		}),
-		generate.WithMatcher(match.AllOf(
-			match.ManagedListModernCore(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write core API managed resource list methods")
-}
-
-// GenerateManagedListLegacyCore generates the resource.ManagedList method set
-// for cluster-scoped (legacy) MRs using the crossplane core API v2 types.
-func GenerateManagedListLegacyCore(filename, header string, p *packages.Package) error {
-	receiver := "l"
-
-	methods := method.Set{
-		"GetItems": method.NewManagedGetItems(receiver, ResourceImport),
+	// Flags take precedence over the configuration file.
+	c, o := loadConfig(*configFile)
+	opts = append(opts, o...)
+	if c != nil && *pattern == "" && len(c.Packages) > 0 {
+		cfg.Dir = c.Dir
+		patterns = c.Packages
 	}
 
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{
-			ResourceImport: ResourceAlias,
-		}),
-		generate.WithMatcher(match.AllOf(
-			match.ManagedListLegacyCore(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write core API cluster managed resource list methods")
-}
-
-// GenerateProviderConfig generates the resource.ProviderConfig method set.
-func GenerateProviderConfig(filename, header string, p *packages.Package) error {
-	receiver := "p"
-
-	methods := method.Set{
-		"SetUsers":      method.NewSetUsers(receiver),
-		"GetUsers":      method.NewGetUsers(receiver),
-		"SetConditions": method.NewSetConditions(receiver, RuntimeImport),
-		"GetCondition":  method.NewGetCondition(receiver, RuntimeImport),
+	if *headerFile != "" {
+		h, err := os.ReadFile(*headerFile)
+		kingpin.FatalIfError(err, "cannot read header file %s", *headerFile)
+		opts = append(opts, angryjet.WithHeader(string(h)))
 	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{RuntimeImport: RuntimeAlias}),
-		generate.WithMatcher(match.AllOf(
-			match.ProviderConfig(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write provider config methods")
-}
-
-// GenerateProviderConfigCore generates the resource.ProviderConfig method set
-// using the crossplane core API v2 types. A ProviderConfig's method set does not
-// vary by scope, so it needs no separate cluster-scoped and namespaced variants
-// like managed resources and provider config usages do.
-func GenerateProviderConfigCore(filename, header string, p *packages.Package) error {
-	receiver := "p"
-
-	methods := method.Set{
-		"SetUsers":      method.NewSetUsers(receiver),
-		"GetUsers":      method.NewGetUsers(receiver),
-		"SetConditions": method.NewSetConditions(receiver, RuntimeV2Import),
-		"GetCondition":  method.NewGetCondition(receiver, RuntimeV2Import),
+	opts = append(opts, withModulePaths(*modulePaths)...)
+	if *filter != "" {
+		opts = append(opts, angryjet.WithFilter(*filter))
 	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{RuntimeV2Import: RuntimeV2Alias}),
-		generate.WithMatcher(match.AllOf(
-			match.ProviderConfigCore(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write core API provider config methods")
-}
-
-// GenerateProviderConfigUsageLegacy generates the resource.ProviderConfigUsage method set.
-func GenerateProviderConfigUsageLegacy(filename, header string, p *packages.Package) error {
-	receiver := "p"
-
-	methods := method.Set{
-		"SetProviderConfigReference": method.NewSetRootProviderConfigReference(receiver, RuntimeImport),
-		"GetProviderConfigReference": method.NewGetRootProviderConfigReference(receiver, RuntimeImport),
-		"SetResourceReference":       method.NewSetRootResourceReference(receiver, RuntimeImport),
-		"GetResourceReference":       method.NewGetRootResourceReference(receiver, RuntimeImport),
+	for _, f := range filenames {
+		// Flags set using environment variables aren't considered set by
+		// the user, so we also check for a value other than the default.
+		if f.set || *f.value != angryjet.Filename(f.output) {
+			opts = append(opts, angryjet.WithFilename(f.output, *f.value))
+		}
 	}
 
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{RuntimeImport: RuntimeAlias}),
-		generate.WithMatcher(match.AllOf(
-			match.ProviderConfigUsageLegacy(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write provider config usage methods")
-}
+	files, genErr := angryjet.Generate(cfg, patterns, opts...)
 
-// GenerateProviderConfigUsageModern generates the v2.ProviderConfigUsage method set.
-func GenerateProviderConfigUsageModern(filename, header string, p *packages.Package) error {
-	receiver := "p"
-
-	methods := method.Set{
-		"SetProviderConfigReference": method.NewSetRootProviderConfigTypedReference(receiver, RuntimeImport),
-		"GetProviderConfigReference": method.NewGetRootProviderConfigTypedReference(receiver, RuntimeImport),
-		"SetResourceReference":       method.NewSetRootResourceReference(receiver, RuntimeImport),
-		"GetResourceReference":       method.NewGetRootResourceReference(receiver, RuntimeImport),
+	// Packages that can be generated are processed even if others can't, so
+	// that every problem is reported in one run.
+	if genErr != nil {
+		app.Errorf("cannot generate methods for packages %s:\n%s", strings.Join(patterns, " "), genErr)
 	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{RuntimeImport: RuntimeAlias}),
-		generate.WithMatcher(match.AllOf(
-			match.ProviderConfigUsageModern(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write provider config usage methods")
-}
-
-// GenerateProviderConfigUsageLegacyCore generates the resource.ProviderConfigUsage
-// method set for provider config usages that embed the core API v2 non-typed
-// ProviderConfigUsage.
-func GenerateProviderConfigUsageLegacyCore(filename, header string, p *packages.Package) error {
-	receiver := "p"
-
-	methods := method.Set{
-		"SetProviderConfigReference": method.NewSetRootProviderConfigReference(receiver, RuntimeV2Import),
-		"GetProviderConfigReference": method.NewGetRootProviderConfigReference(receiver, RuntimeV2Import),
-		"SetResourceReference":       method.NewSetRootResourceReference(receiver, RuntimeV2Import),
-		"GetResourceReference":       method.NewGetRootResourceReference(receiver, RuntimeV2Import),
+	kingpin.FatalIfError(process(files, *check, *dryRun, *showDiff), "")
+	if genErr != nil {
+		os.Exit(1)
 	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{RuntimeV2Import: RuntimeV2Alias}),
-		generate.WithMatcher(match.AllOf(
-			match.ProviderConfigUsageLegacyCore(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write core API provider config usage methods")
 }
 
-// GenerateProviderConfigUsageModernCore generates the resource.ProviderConfigUsage
-// method set for provider config usages that embed the core API v2
-// TypedProviderConfigUsage.
-func GenerateProviderConfigUsageModernCore(filename, header string, p *packages.Package) error {
-	receiver := "p"
-
-	methods := method.Set{
-		"SetProviderConfigReference": method.NewSetRootProviderConfigTypedReference(receiver, RuntimeV2Import),
-		"GetProviderConfigReference": method.NewGetRootProviderConfigTypedReference(receiver, RuntimeV2Import),
-		"SetResourceReference":       method.NewSetRootResourceReference(receiver, RuntimeV2Import),
-		"GetResourceReference":       method.NewGetRootResourceReference(receiver, RuntimeV2Import),
-	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{RuntimeV2Import: RuntimeV2Alias}),
-		generate.WithMatcher(match.AllOf(
-			match.ProviderConfigUsageModernCore(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write core API typed provider config usage methods")
+// A config file, and the directory it was loaded from.
+type config struct {
+	*angryjet.Config
+	Dir string
 }
 
-// GenerateProviderConfigUsageListLegacy generates the
-// resource.ProviderConfigUsageList method set.
-func GenerateProviderConfigUsageListLegacy(filename, header string, p *packages.Package) error {
-	receiver := "p"
-
-	methods := method.Set{
-		"GetItems": method.NewProviderConfigUsageGetItems(receiver, ResourceImport),
+// loadConfig loads the supplied configuration file, or the one at the root of
+// the current module if none is supplied. It returns a nil config if there is
+// no configuration file.
+func loadConfig(path string) (*config, []angryjet.Option) {
+	if path == "" {
+		p, err := angryjet.FindConfig(".")
+		kingpin.FatalIfError(err, "cannot find configuration file")
+		path = p
 	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{ResourceImport: ResourceAlias}),
-		generate.WithMatcher(match.AllOf(
-			match.ProviderConfigUsageListLegacy(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write provider config usage list methods")
-}
-
-// GenerateProviderConfigUsageListModern generates the
-// resource.ProviderConfigUsageList method set
-// for XPv2 namespaced MRs.
-func GenerateProviderConfigUsageListModern(filename, header string, p *packages.Package) error {
-	receiver := "p"
-
-	methods := method.Set{
-		"GetItems": method.NewProviderConfigUsageGetItems(receiver, ResourceImport),
+	if path == "" {
+		return nil, nil
 	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{ResourceImport: ResourceAlias}),
-		generate.WithMatcher(match.AllOf(
-			match.ProviderConfigUsageListModern(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write V2 provider config usage list methods")
+	c, err := angryjet.LoadConfig(path)
+	kingpin.FatalIfError(err, "cannot load configuration file")
+	o, err := c.Options(filepath.Dir(path))
+	kingpin.FatalIfError(err, "cannot load configuration file %s", path)
+	return &config{Config: c, Dir: filepath.Dir(path)}, o
 }
 
-// GenerateProviderConfigUsageListLegacyCore generates the
-// resource.ProviderConfigUsageList method set for provider config usages that
-// embed the core API v2 non-typed ProviderConfigUsage.
-func GenerateProviderConfigUsageListLegacyCore(filename, header string, p *packages.Package) error {
-	receiver := "p"
-
-	methods := method.Set{
-		"GetItems": method.NewProviderConfigUsageGetItems(receiver, ResourceImport),
+// withModulePaths returns an option for each of the supplied module paths.
+func withModulePaths(m map[string]string) []angryjet.Option {
+	o := make([]angryjet.Option, 0, len(m))
+	for module, replacement := range m {
+		o = append(o, angryjet.WithModulePath(module, replacement))
 	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{ResourceImport: ResourceAlias}),
-		generate.WithMatcher(match.AllOf(
-			match.ProviderConfigUsageListLegacyCore(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write core API provider config usage list methods")
+	return o
 }
 
-// GenerateProviderConfigUsageListModernCore generates the
-// resource.ProviderConfigUsageList method set for provider config usages that
-// embed the core API v2 TypedProviderConfigUsage.
-func GenerateProviderConfigUsageListModernCore(filename, header string, p *packages.Package) error {
-	receiver := "p"
-
-	methods := method.Set{
-		"GetItems": method.NewProviderConfigUsageGetItems(receiver, ResourceImport),
-	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{ResourceImport: ResourceAlias}),
-		generate.WithMatcher(match.AllOf(
-			match.ProviderConfigUsageListModernCore(),
-			match.DoesNotHaveMarker(comments.In(p), DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write core API typed provider config usage list methods")
+// newFilename adds a flag that configures the filename of the supplied output.
+func newFilename(cmd *kingpin.CmdClause, output, name, help string) *filename {
+	f := &filename{output: output}
+	f.value = cmd.Flag(name, help).Default(angryjet.Filename(output)).IsSetByUser(&f.set).String()
+	return f
 }
 
-// GenerateReferencesLegacy generates reference resolver calls.
-func GenerateReferencesLegacy(filename, header string, p *packages.Package) error {
-	receiver := "mg"
-	comm := comments.In(p)
-
-	methods := method.Set{
-		"ResolveReferences": method.NewResolveReferences(types.NewTraverser(comm), receiver, ClientImport, ReferenceImport),
+// process the supplied generated files, either writing, verifying or
+// describing them.
+func process(files []angryjet.File, check, dryRun, showDiff bool) error {
+	if check {
+		return errors.Wrap(angryjet.Verify(files), "cannot verify generated files")
 	}
 
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{
-			ClientImport:    ClientAlias,
-			ReferenceImport: ReferenceAlias,
-		}),
-		generate.WithMatcher(match.AllOf(
-			match.ManagedLegacy(),
-			match.DoesNotHaveMarker(comm, DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write reference resolver methods")
-}
-
-// GenerateReferencesModern generates reference resolver calls for XPv2 namespaced MRs.
-func GenerateReferencesModern(filename, header string, p *packages.Package) error {
-	receiver := "mg"
-	comm := comments.In(p)
-
-	methods := method.Set{
-		"ResolveReferences": method.NewResolveReferencesV2(types.NewTraverser(comm), receiver, ClientImport, ReferenceImport),
+	if !dryRun && !showDiff {
+		return errors.Wrap(angryjet.Write(files), "cannot write generated files")
 	}
 
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{
-			ClientImport:    ClientAlias,
-			ReferenceImport: ReferenceAlias,
-		}),
-		generate.WithMatcher(match.AllOf(
-			match.ManagedModern(),
-			match.DoesNotHaveMarker(comm, DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write V2 reference resolver methods")
-}
-
-// GenerateReferencesModernCore generates reference resolver calls for namespaced
-// (modern) MRs using the crossplane core API v2 types.
-func GenerateReferencesModernCore(filename, header string, p *packages.Package) error {
-	receiver := "mg"
-	comm := comments.In(p)
-
-	methods := method.Set{
-		"ResolveReferences": method.NewResolveReferencesV2(types.NewTraverser(comm), receiver, ClientImport, ReferenceImport),
+	changes, err := angryjet.Plan(files)
+	if err != nil {
+		return errors.Wrap(err, "cannot compare generated files")
 	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{
-			ClientImport:    ClientAlias,
-			ReferenceImport: ReferenceAlias,
-		}),
-		generate.WithMatcher(match.AllOf(
-			match.ManagedModernCore(),
-			match.DoesNotHaveMarker(comm, DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write core API reference resolver methods")
-}
-
-// GenerateReferencesLegacyCore generates reference resolver calls for
-// cluster-scoped (legacy) MRs using the crossplane core API v2 types.
-func GenerateReferencesLegacyCore(filename, header string, p *packages.Package) error {
-	receiver := "mg"
-	comm := comments.In(p)
-
-	methods := method.Set{
-		"ResolveReferences": method.NewResolveReferences(types.NewTraverser(comm), receiver, ClientImport, ReferenceImport),
+	for _, c := range changes {
+		if c.Action == angryjet.ActionUnchanged {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("%s %s\n", c.Action, c.Path)
+		}
+		if showDiff {
+			fmt.Print(c.Diff())
+		}
 	}
-
-	err := generate.WriteMethods(p, methods, filepath.Join(filepath.Dir(p.GoFiles[0]), filename),
-		generate.WithHeaders(header),
-		generate.WithImportAliases(map[string]string{
-			ClientImport:    ClientAlias,
-			ReferenceImport: ReferenceAlias,
-		}),
-		generate.WithMatcher(match.AllOf(
-			match.ManagedLegacyCore(),
-			match.DoesNotHaveMarker(comm, DisableMarker, "false")),
-		),
-	)
-
-	return errors.Wrap(err, "cannot write core API cluster reference resolver methods")
+	return nil
 }
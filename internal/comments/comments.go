@@ -55,27 +55,57 @@ func In(p *packages.Package) Comments {
 
 // For returns the comments for the supplied Object, if any.
 func (c Comments) For(o types.Object) string {
+	return c.groupFor(o).Text()
+}
+
+func (c Comments) groupFor(o types.Object) *ast.CommentGroup {
 	p := c.fset.Position(o.Pos())
-	return c.groups[fl{Filename: p.Filename, Line: p.Line - 1}].Text()
+	return c.groups[fl{Filename: p.Filename, Line: p.Line - 1}]
+}
+
+// Position returns the position of the supplied Object.
+func (c Comments) Position(o types.Object) token.Position {
+	return c.fset.Position(o.Pos())
 }
 
 // Before returns the comments before the supplied Object, if any. A comment is
 // deemed to be 'before' (rather than 'for') an Object if it ends exactly one
 // blank line above where the Object (including its comment, if any) begins.
 func (c Comments) Before(o types.Object) string {
+	return c.groupBefore(o).Text()
+}
+
+func (c Comments) groupBefore(o types.Object) *ast.CommentGroup {
 	p := c.fset.Position(o.Pos())
 	g := c.groups[fl{Filename: p.Filename, Line: p.Line - 1}]
 
 	if g == nil {
 		// No comment group ends immediately before this object. Check for one
 		// ending two lines back.
-		return c.groups[fl{Filename: p.Filename, Line: p.Line - 2}].Text()
+		return c.groups[fl{Filename: p.Filename, Line: p.Line - 2}]
 	}
 
 	// A comment group ends immediately before this object. Check for another
 	// one ending two lines back from where it starts.
 	start := c.fset.Position(g.List[0].Slash)
-	return c.groups[fl{Filename: start.Filename, Line: start.Line - 2}].Text()
+	return c.groups[fl{Filename: start.Filename, Line: start.Line - 2}]
+}
+
+// MarkerPosition returns the position of the supplied Object's comment marker
+// with the supplied key, or the position of the Object if it has no such
+// marker. Markers are looked for in the comments for and before the Object.
+func (c Comments) MarkerPosition(o types.Object, k string) token.Position {
+	for _, g := range []*ast.CommentGroup{c.groupFor(o), c.groupBefore(o)} {
+		if g == nil {
+			continue
+		}
+		for _, cm := range g.List {
+			if _, ok := ParseMarkers(strings.TrimPrefix(cm.Text, "//"))[k]; ok {
+				return c.fset.Position(cm.Slash)
+			}
+		}
+	}
+	return c.Position(o)
 }
 
 // Markers are comments that begin with a special character (typically
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diagnostic reports errors at positions in Go source files.
+package diagnostic
+
+import (
+	"go/token"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// An Error that occurred at a position in a Go source file.
+type Error struct {
+	Position token.Position
+	Err      error
+}
+
+// Error returns the underlying error's message, without its position. Use
+// Format to include the position.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// At returns the supplied error annotated with the supplied position. It
+// returns nil if the supplied error is nil.
+func At(pos token.Position, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Position: pos, Err: err}
+}
+
+// Errorf returns an error at the supplied position.
+func Errorf(pos token.Position, format string, args ...any) error {
+	return At(pos, errors.Errorf(format, args...))
+}
+
+// Wrapf returns the supplied error annotated with the supplied message. Each
+// error of an Errors is annotated, so that the message isn't lost when Append
+// flattens the Errors. It returns nil if the supplied error is nil.
+func Wrapf(err error, format string, args ...any) error {
+	var l Errors
+	if !errors.As(err, &l) {
+		return errors.Wrapf(err, format, args...)
+	}
+	out := make(Errors, len(l))
+	for i, e := range l {
+		out[i] = Wrapf(e, format, args...)
+	}
+	return out
+}
+
+// Position returns the most precise position of the supplied error, i.e. the
+// position annotated closest to the root cause of the error.
+func Position(err error) (token.Position, bool) {
+	pos, found := token.Position{}, false
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if d, ok := e.(*Error); ok && d.Position.IsValid() {
+			pos, found = d.Position, true
+		}
+	}
+	return pos, found
+}
+
+// Format returns the supplied error's message prefixed with its position, if
+// it has one, for example "types.go:12:2: invalid marker".
+func Format(err error) string {
+	if pos, ok := Position(err); ok {
+		return pos.String() + ": " + err.Error()
+	}
+	return err.Error()
+}
+
+// Errors is a list of errors, each of which may have a position.
+type Errors []error
+
+// Append returns the list with the supplied errors appended. Nil errors are
+// ignored, and Errors are flattened into the list.
+func (e Errors) Append(errs ...error) Errors {
+	for _, err := range errs {
+		var l Errors
+		switch {
+		case err == nil:
+		case errors.As(err, &l):
+			e = e.Append(l...)
+		default:
+			e = append(e, err)
+		}
+	}
+	return e
+}
+
+// Err returns the list as an error, or nil if the list is empty.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error returns each error, formatted with its position, on its own line.
+func (e Errors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = Format(err)
+	}
+	return strings.Join(s, "\n")
+}
+
+// Unwrap returns the errors.
+func (e Errors) Unwrap() []error {
+	return e
+}
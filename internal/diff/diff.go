@@ -0,0 +1,252 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diff computes line based unified diffs.
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Context is the number of unchanged lines shown around each change.
+const Context = 3
+
+type kind int
+
+const (
+	equal kind = iota
+	del
+	ins
+)
+
+type edit struct {
+	kind kind
+	line string
+}
+
+// Unified returns a unified diff that transforms a, named oldName, into b,
+// named newName. It returns an empty string if a and b are equal.
+func Unified(oldName, newName string, a, b []byte) string {
+	if string(a) == string(b) {
+		return ""
+	}
+	edits := myers(lines(a), lines(b))
+
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", oldName, newName)
+
+	// Walk the edit script, emitting a hunk for each run of changes along
+	// with their surrounding context. Runs separated by no more than twice
+	// the context are merged into one hunk.
+	ai, bi := 0, 0
+	for i := 0; i < len(edits); {
+		if edits[i].kind == equal {
+			ai, bi, i = ai+1, bi+1, i+1
+			continue
+		}
+
+		start := max(i-Context, 0)
+		for j := start; j < i; j++ {
+			ai, bi = ai-1, bi-1
+		}
+		end := i
+		for end < len(edits) {
+			if edits[end].kind != equal {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].kind == equal {
+				run++
+			}
+			if run == len(edits) || run-end > 2*Context {
+				end = min(end+Context, len(edits))
+				break
+			}
+			end = run
+		}
+
+		hunk := &strings.Builder{}
+		as, bs := 0, 0
+		for _, e := range edits[start:end] {
+			switch e.kind {
+			case equal:
+				as, bs = as+1, bs+1
+				hunk.WriteString(" " + e.line)
+			case del:
+				as++
+				hunk.WriteString("-" + e.line)
+			case ins:
+				bs++
+				hunk.WriteString("+" + e.line)
+			}
+			if !strings.HasSuffix(e.line, "\n") {
+				hunk.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		fmt.Fprintf(out, "@@ -%s +%s @@\n%s", span(ai, as), span(bi, bs), hunk)
+
+		ai, bi, i = ai+as, bi+bs, end
+	}
+	return out.String()
+}
+
+// span formats a hunk range. Empty ranges refer to the line before them.
+func span(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+// lines splits the supplied data into lines, retaining line endings.
+func lines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	l := strings.SplitAfter(string(data), "\n")
+	if l[len(l)-1] == "" {
+		l = l[:len(l)-1]
+	}
+	return l
+}
+
+// myers returns the shortest edit script that transforms a into b, per
+// Eugene W. Myers' "An O(ND) Difference Algorithm and Its Variations". It
+// uses the linear space variant of the algorithm, which finds the middle snake
+// of an edit script and recurses on either side of it.
+func myers(a, b []string) []edit {
+	edits := compare(make([]edit, 0, len(a)+len(b)), a, b)
+
+	// Show the deletions of each run of changes before its insertions.
+	for i := 0; i < len(edits); {
+		if edits[i].kind == equal {
+			i++
+			continue
+		}
+		j := i
+		for j < len(edits) && edits[j].kind != equal {
+			j++
+		}
+		slices.SortStableFunc(edits[i:j], func(x, y edit) int { return int(x.kind) - int(y.kind) })
+		i = j
+	}
+	return edits
+}
+
+// compare appends the shortest edit script that transforms a into b to the
+// supplied edits.
+func compare(edits []edit, a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for _, l := range a[:prefix] {
+		edits = append(edits, edit{kind: equal, line: l})
+	}
+	a, b = a[prefix:], b[prefix:]
+
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, l := range b {
+			edits = append(edits, edit{kind: ins, line: l})
+		}
+	case len(b) == 0:
+		for _, l := range a {
+			edits = append(edits, edit{kind: del, line: l})
+		}
+	default:
+		// Both a and b are non-empty, and differ in their first and last
+		// lines, so the script has at least two edits, and there is at
+		// least one on either side of the middle snake.
+		x, y, u, v := middleSnake(a, b)
+		edits = compare(edits, a[:x], b[:y])
+		for _, l := range a[x:u] {
+			edits = append(edits, edit{kind: equal, line: l})
+		}
+		edits = compare(edits, a[u:], b[v:])
+	}
+
+	for _, l := range common {
+		edits = append(edits, edit{kind: equal, line: l})
+	}
+	return edits
+}
+
+// middleSnake returns the start (x, y) and end (u, v) of the middle snake of
+// the shortest edit script that transforms a into b. It searches forwards from
+// the start of a and b and backwards from their end at the same time, until
+// the searches overlap.
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+	offset := limit + 1
+
+	// The furthest x reached on each diagonal k = x - y, searching forwards
+	// and backwards. Backward searches use coordinates from the end of a and
+	// b, so a backward diagonal k is the forward diagonal delta - k.
+	fwd := make([]int, 2*offset+1)
+	bwd := make([]int, 2*offset+1)
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := fwd[offset+k-1] + 1
+			if k == -d || (k != d && fwd[offset+k-1] < fwd[offset+k+1]) {
+				x = fwd[offset+k+1]
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			fwd[offset+k] = x
+			if odd && delta-k >= -(d-1) && delta-k <= d-1 && x+bwd[offset+delta-k] >= n {
+				return sx, sy, x, y
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			x := bwd[offset+k-1] + 1
+			if k == -d || (k != d && bwd[offset+k-1] < bwd[offset+k+1]) {
+				x = bwd[offset+k+1]
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x, y = x+1, y+1
+			}
+			bwd[offset+k] = x
+			if !odd && delta-k >= -d && delta-k <= d && x+fwd[offset+delta-k] >= n {
+				return n - x, m - y, n - sx, m - sy
+			}
+		}
+	}
+	// Unreachable; the searches overlap once d reaches half the length of
+	// the shortest edit script.
+	return 0, 0, n, m
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnified(t *testing.T) {
+	type args struct {
+		a string
+		b string
+	}
+	cases := map[string]struct {
+		args args
+		want string
+	}{
+		"Equal": {
+			args: args{a: "a\nb\n", b: "a\nb\n"},
+			want: "",
+		},
+		"Create": {
+			args: args{a: "", b: "a\nb\n"},
+			want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		"Delete": {
+			args: args{a: "a\nb\n", b: ""},
+			want: "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		"Change": {
+			args: args{a: "1\n2\n3\n4\n5\n6\n7\n8\n9\n", b: "1\n2\n3\n4\nfive\n6\n7\n8\n9\n"},
+			want: "--- old\n+++ new\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		"SeparateHunks": {
+			args: args{a: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", b: "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n"},
+			want: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+		"MergedHunks": {
+			args: args{a: "1\n2\n3\n4\n5\n6\n7\n", b: "one\n2\n3\n4\n5\n6\nseven\n"},
+			want: "--- old\n+++ new\n@@ -1,7 +1,7 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n-7\n+seven\n",
+		},
+		"NoTrailingNewline": {
+			args: args{a: "a\nb", b: "a\nb\n"},
+			want: "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Unified("old", "new", []byte(tc.args.a), []byte(tc.args.b))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unified(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnifiedLargeFiles(t *testing.T) {
+	large, edited := &strings.Builder{}, &strings.Builder{}
+	for i := range 8000 {
+		fmt.Fprintf(large, "line %d\n", i)
+		if i%10 == 0 {
+			fmt.Fprintf(edited, "edited line %d\n", i)
+			continue
+		}
+		fmt.Fprintf(edited, "line %d\n", i)
+	}
+
+	cases := map[string]struct {
+		reason string
+		a      string
+		b      string
+		want   string
+	}{
+		"Create": {
+			reason: "Diffing a large new file should use memory linear in its size.",
+			b:      large.String(),
+			want:   "+line 7999\n",
+		},
+		"Delete": {
+			reason: "Diffing a large deleted file should use memory linear in its size.",
+			a:      large.String(),
+			want:   "-line 7999\n",
+		},
+		"Edit": {
+			reason: "Diffing a large file with many changes should use memory linear in its size.",
+			a:      large.String(),
+			b:      edited.String(),
+			want:   "-line 7990\n+edited line 7990\n",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			got := Unified("old", "new", []byte(tc.a), []byte(tc.b))
+			runtime.ReadMemStats(&after)
+
+			if !strings.Contains(got, tc.want) {
+				t.Errorf("\n%s\nUnified(...): want diff containing %q", tc.reason, tc.want)
+			}
+			if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 32<<20 {
+				t.Errorf("\n%s\nUnified(...): want at most 32MiB allocated, got %d bytes", tc.reason, alloc)
+			}
+		})
+	}
+}
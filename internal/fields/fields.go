@@ -19,6 +19,8 @@ package fields
 
 import (
 	"go/types"
+	"iter"
+	"slices"
 	"strings"
 )
 
@@ -41,6 +43,16 @@ const (
 	NameResourceV2Spec             = "ManagedResourceSpec"
 	NameClusterManagedResourceSpec = "ClusterManagedResourceSpec"
 	NameManagedResourceStatus      = "ManagedResourceStatus"
+
+	NameCompositeResourceSpec   = "CompositeResourceSpec"
+	NameCompositeResourceStatus = "CompositeResourceStatus"
+	NameCompositeClaimSpec      = "CompositeClaimSpec"
+	NameCompositeClaimStatus    = "CompositeClaimStatus"
+
+	NamePackageSpec           = "PackageSpec"
+	NamePackageStatus         = "PackageStatus"
+	NamePackageRevisionSpec   = "PackageRevisionSpec"
+	NamePackageRevisionStatus = "PackageRevisionStatus"
 )
 
 // Field type suffixes.
@@ -72,6 +84,18 @@ const (
 	TypeSuffixProviderConfigStatusCore       = "github.com/crossplane/crossplane/apis/v2/core/v2.ProviderConfigStatus"
 	TypeSuffixProviderConfigUsageCore        = "github.com/crossplane/crossplane/apis/v2/core/v2.ProviderConfigUsage"
 	TypeSuffixTypedProviderConfigUsageCore   = "github.com/crossplane/crossplane/apis/v2/core/v2.TypedProviderConfigUsage"
+	TypeSuffixCompositeResourceSpecCore      = "github.com/crossplane/crossplane/apis/v2/core/v2.CompositeResourceSpec"
+	TypeSuffixCompositeResourceStatusCore    = "github.com/crossplane/crossplane/apis/v2/core/v2.CompositeResourceStatus"
+	TypeSuffixCompositeClaimSpecCore         = "github.com/crossplane/crossplane/apis/v2/core/v2.CompositeClaimSpec"
+	TypeSuffixCompositeClaimStatusCore       = "github.com/crossplane/crossplane/apis/v2/core/v2.CompositeClaimStatus"
+
+	// TypeSuffixPackageSpec and the other package suffixes below live in the
+	// crossplane/apis/v2/pkg/v1 module, alongside Crossplane's own Provider,
+	// Function and Configuration types.
+	TypeSuffixPackageSpec           = "github.com/crossplane/crossplane/apis/v2/pkg/v1.PackageSpec"
+	TypeSuffixPackageStatus         = "github.com/crossplane/crossplane/apis/v2/pkg/v1.PackageStatus"
+	TypeSuffixPackageRevisionSpec   = "github.com/crossplane/crossplane/apis/v2/pkg/v1.PackageRevisionSpec"
+	TypeSuffixPackageRevisionStatus = "github.com/crossplane/crossplane/apis/v2/pkg/v1.PackageRevisionStatus"
 )
 
 func matches(s *types.Struct, m Matcher) bool {
@@ -105,7 +129,7 @@ func (o Matcher) Or(m Matcher) Matcher {
 // Has returns true if the supplied Object's underlying type is struct (or a
 // slice or map of struct), and it matches all of the supplied field Matchers.
 func Has(o types.Object, m ...Matcher) bool {
-	s := findStruct(o)
+	s := Struct(o)
 	if s == nil {
 		return false
 	}
@@ -117,7 +141,59 @@ func Has(o types.Object, m ...Matcher) bool {
 	return true
 }
 
-func findStruct(o types.Object) *types.Struct {
+// HasPromoted is like Has, except that fields promoted from structs embedded at
+// any depth also satisfy the supplied Matchers. A field of a Spec that embeds a
+// CommonSpec that embeds a ResourceSpec thus satisfies IsResourceSpec.
+func HasPromoted(o types.Object, m ...Matcher) bool {
+	s := Struct(o)
+	if s == nil {
+		return false
+	}
+	for _, matcher := range m {
+		found := false
+		for f := range Promoted(s) {
+			if matcher(f) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Promoted returns the fields of the supplied struct, followed by the fields
+// promoted from its embedded structs at any depth, breadth first. Each struct
+// is visited once, so recursively embedded structs are safe.
+func Promoted(s *types.Struct) iter.Seq[*types.Var] {
+	return func(yield func(*types.Var) bool) {
+		seen := map[*types.Struct]bool{}
+		queue := []*types.Struct{s}
+		for len(queue) > 0 {
+			s := queue[0]
+			queue = queue[1:]
+			if seen[s] {
+				continue
+			}
+			seen[s] = true
+			for f := range s.Fields() {
+				if !yield(f) {
+					return
+				}
+				if es := Struct(f); f.Embedded() && es != nil {
+					queue = append(queue, es)
+				}
+			}
+		}
+	}
+}
+
+// Struct returns the struct underlying the supplied Object's type, or the
+// struct element of its slice, map or pointer type. It returns nil if there is
+// no such struct.
+func Struct(o types.Object) *types.Struct {
 	switch t := o.Type().Underlying().(type) {
 	case *types.Struct:
 		return t
@@ -171,13 +247,105 @@ func IsNamed(name string) Matcher {
 }
 
 // IsTypeNamed returns a Matcher that returns true if the supplied field has the
-// supplied type name suffix and name.
-func IsTypeNamed(typeNameSuffix, name string) Matcher {
+// supplied type name suffix and name. A suffix that is qualified by a package
+// path, like TypeSuffixResourceSpec, is matched by the identity of the field's
+// type: it must be the named type of exactly that package, or of the same
+// package in one of these modules' accepted forks, or remapped module. The
+// suffix is only matched against the field's type name when its package could
+// not be loaded.
+func (m Modules) IsTypeNamed(typeNameSuffix, name string) Matcher {
+	i := strings.LastIndex(typeNameSuffix, ".")
+	path, typeName := typeNameSuffix[:max(i, 0)], typeNameSuffix[i+1:]
+	paths := m.accepted(path)
 	return func(f *types.Var) bool {
 		if !IsNamed(name)(f) {
 			return false
 		}
-		return strings.HasSuffix(f.Type().String(), typeNameSuffix)
+		n := named(f.Type())
+		if path == "" || n == nil || n.Obj().Pkg() == nil || !n.Obj().Pkg().Complete() {
+			return strings.HasSuffix(f.Type().String(), typeNameSuffix)
+		}
+		return n.Obj().Name() == typeName && slices.Contains(paths, n.Obj().Pkg().Path())
+	}
+}
+
+// named returns the named type of the supplied type, or of the type it points
+// to. It returns nil if neither is a named type.
+func named(t types.Type) *types.Named {
+	if p, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	n, _ := types.Unalias(t).(*types.Named)
+	return n
+}
+
+// Forks maps the path of a module whose types fields are matched against, like
+// github.com/crossplane/crossplane-runtime/v2, to the paths of modules whose
+// types are accepted in its place, like forks of it.
+type Forks map[string][]string
+
+// Modules configures which packages the Matchers of well known types, such as
+// IsResourceSpec, accept the types of. The zero value accepts only the
+// packages the well known types are declared in.
+type Modules struct {
+	// Forks whose types are accepted in addition to those of the module they
+	// fork.
+	Forks Forks
+
+	// Remapped maps the path of a module to the path of the module whose
+	// types are matched instead of its own.
+	Remapped map[string]string
+}
+
+// InModule returns the remainder of the supplied package path after the
+// supplied module path, and true if the package is in that module.
+func InModule(path, module string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, module)
+	if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+		return "", false
+	}
+	return rest, true
+}
+
+// Remap returns the path of the supplied package in the module its module
+// maps to in the supplied map, if any. The longest matching module wins.
+func Remap(path string, m map[string]string) string {
+	module := ""
+	for from := range m {
+		if _, ok := InModule(path, from); ok && len(from) > len(module) {
+			module = from
+		}
+	}
+	if module == "" {
+		return path
+	}
+	rest, _ := InModule(path, module)
+	return m[module] + rest
+}
+
+// accepted returns the path of the supplied package, remapped if its module
+// is, and the paths of the same package in each accepted fork of its module.
+func (m Modules) accepted(path string) []string {
+	path = Remap(path, m.Remapped)
+	paths := []string{path}
+	for module, fs := range m.Forks {
+		rest, ok := InModule(path, module)
+		if !ok {
+			continue
+		}
+		for _, fork := range fs {
+			paths = append(paths, fork+rest)
+		}
+	}
+	return paths
+}
+
+// HasPromotedFieldThat returns a Matcher that returns true if the supplied
+// field is a struct that, including the fields promoted from its embedded
+// structs, matches the supplied field matchers.
+func HasPromotedFieldThat(m ...Matcher) Matcher {
+	return func(f *types.Var) bool {
+		return HasPromoted(f, m...)
 	}
 }
 
@@ -191,104 +359,164 @@ func HasFieldThat(m ...Matcher) Matcher {
 
 // IsTypeMeta returns a Matcher that returns true if the supplied field appears
 // to be Kubernetes type metadata.
-func IsTypeMeta() Matcher { return IsTypeNamed(TypeSuffixTypeMeta, NameTypeMeta) }
+func (m Modules) IsTypeMeta() Matcher { return m.IsTypeNamed(TypeSuffixTypeMeta, NameTypeMeta) }
 
 // IsObjectMeta returns a Matcher that returns true if the supplied field
 // appears to be Kubernetes object metadata.
-func IsObjectMeta() Matcher { return IsTypeNamed(TypeSuffixObjectMeta, NameObjectMeta) }
+func (m Modules) IsObjectMeta() Matcher { return m.IsTypeNamed(TypeSuffixObjectMeta, NameObjectMeta) }
 
 // IsListMeta returns a Matcher that returns true if the supplied field appears
 // to be Kubernetes list metadata.
-func IsListMeta() Matcher { return IsTypeNamed(TypeSuffixListMeta, NameListMeta) }
+func (m Modules) IsListMeta() Matcher { return m.IsTypeNamed(TypeSuffixListMeta, NameListMeta) }
 
 // IsSpec returns a Matcher that returns true if the supplied field appears to
 // be a Kubernetes resource spec.
-func IsSpec() Matcher { return IsTypeNamed(TypeSuffixSpec, NameSpec) }
+func (m Modules) IsSpec() Matcher { return m.IsTypeNamed(TypeSuffixSpec, NameSpec) }
 
 // IsSpecTemplate returns a Matcher that returns true if the supplied field
 // appears to be a Crossplane resource class spec template.
-func IsSpecTemplate() Matcher { return IsTypeNamed(TypeSuffixSpecTemplate, NameSpecTemplate) }
+func (m Modules) IsSpecTemplate() Matcher {
+	return m.IsTypeNamed(TypeSuffixSpecTemplate, NameSpecTemplate)
+}
 
 // IsStatus returns a Matcher that returns true if the supplied field appears to
 // be a Kubernetes resource status.
-func IsStatus() Matcher { return IsTypeNamed(TypeSuffixStatus, NameStatus) }
+func (m Modules) IsStatus() Matcher { return m.IsTypeNamed(TypeSuffixStatus, NameStatus) }
 
 // IsResourceSpec returns a Matcher that returns true if the supplied field
 // appears to be a Crossplane managed resource spec.
-func IsResourceSpec() Matcher { return IsTypeNamed(TypeSuffixResourceSpec, NameResourceSpec) }
+func (m Modules) IsResourceSpec() Matcher {
+	return m.IsTypeNamed(TypeSuffixResourceSpec, NameResourceSpec)
+}
 
 // IsResourceV2Spec returns a Matcher that returns true if the supplied field
 // appears to be a Crossplane managed resource spec from crossplane-runtime.
-func IsResourceV2Spec() Matcher { return IsTypeNamed(TypeSuffixResourceV2Spec, NameResourceV2Spec) }
+func (m Modules) IsResourceV2Spec() Matcher {
+	return m.IsTypeNamed(TypeSuffixResourceV2Spec, NameResourceV2Spec)
+}
 
 // IsManagedResourceSpecCore returns a Matcher that returns true if the supplied
 // field appears to be a namespaced Crossplane managed resource spec from the
 // core API v2 module.
-func IsManagedResourceSpecCore() Matcher {
-	return IsTypeNamed(TypeSuffixManagedResourceSpecCore, NameResourceV2Spec)
+func (m Modules) IsManagedResourceSpecCore() Matcher {
+	return m.IsTypeNamed(TypeSuffixManagedResourceSpecCore, NameResourceV2Spec)
 }
 
 // IsClusterManagedResourceSpecCore returns a Matcher that returns true if the
 // supplied field appears to be a cluster-scoped Crossplane managed resource spec
 // from the core API v2 module.
-func IsClusterManagedResourceSpecCore() Matcher {
-	return IsTypeNamed(TypeSuffixClusterManagedResourceSpecCore, NameClusterManagedResourceSpec)
+func (m Modules) IsClusterManagedResourceSpecCore() Matcher {
+	return m.IsTypeNamed(TypeSuffixClusterManagedResourceSpecCore, NameClusterManagedResourceSpec)
 }
 
 // IsResourceStatus returns a Matcher that returns true if the supplied field
 // appears to be a Crossplane managed resource status.
-func IsResourceStatus() Matcher { return IsTypeNamed(TypeSuffixResourceStatus, NameResourceStatus) }
+func (m Modules) IsResourceStatus() Matcher {
+	return m.IsTypeNamed(TypeSuffixResourceStatus, NameResourceStatus)
+}
 
 // IsManagedResourceStatusCore returns a Matcher that returns true if the
 // supplied field appears to be a Crossplane managed resource status from the
 // core API v2 module.
-func IsManagedResourceStatusCore() Matcher {
-	return IsTypeNamed(TypeSuffixManagedResourceStatusCore, NameManagedResourceStatus)
+func (m Modules) IsManagedResourceStatusCore() Matcher {
+	return m.IsTypeNamed(TypeSuffixManagedResourceStatusCore, NameManagedResourceStatus)
+}
+
+// IsCompositeResourceSpecCore returns a Matcher that returns true if the
+// supplied field appears to be a Crossplane composite resource spec from the
+// core API v2 module.
+func (m Modules) IsCompositeResourceSpecCore() Matcher {
+	return m.IsTypeNamed(TypeSuffixCompositeResourceSpecCore, NameCompositeResourceSpec)
+}
+
+// IsCompositeResourceStatusCore returns a Matcher that returns true if the
+// supplied field appears to be a Crossplane composite resource status from the
+// core API v2 module.
+func (m Modules) IsCompositeResourceStatusCore() Matcher {
+	return m.IsTypeNamed(TypeSuffixCompositeResourceStatusCore, NameCompositeResourceStatus)
+}
+
+// IsCompositeClaimSpecCore returns a Matcher that returns true if the supplied
+// field appears to be a Crossplane composite resource claim spec from the core
+// API v2 module.
+func (m Modules) IsCompositeClaimSpecCore() Matcher {
+	return m.IsTypeNamed(TypeSuffixCompositeClaimSpecCore, NameCompositeClaimSpec)
+}
+
+// IsCompositeClaimStatusCore returns a Matcher that returns true if the
+// supplied field appears to be a Crossplane composite resource claim status
+// from the core API v2 module.
+func (m Modules) IsCompositeClaimStatusCore() Matcher {
+	return m.IsTypeNamed(TypeSuffixCompositeClaimStatusCore, NameCompositeClaimStatus)
+}
+
+// IsPackageSpec returns a Matcher that returns true if the supplied field
+// appears to be a Crossplane package spec.
+func (m Modules) IsPackageSpec() Matcher {
+	return m.IsTypeNamed(TypeSuffixPackageSpec, NamePackageSpec)
+}
+
+// IsPackageStatus returns a Matcher that returns true if the supplied field
+// appears to be a Crossplane package status.
+func (m Modules) IsPackageStatus() Matcher {
+	return m.IsTypeNamed(TypeSuffixPackageStatus, NamePackageStatus)
+}
+
+// IsPackageRevisionSpec returns a Matcher that returns true if the supplied
+// field appears to be a Crossplane package revision spec.
+func (m Modules) IsPackageRevisionSpec() Matcher {
+	return m.IsTypeNamed(TypeSuffixPackageRevisionSpec, NamePackageRevisionSpec)
+}
+
+// IsPackageRevisionStatus returns a Matcher that returns true if the supplied
+// field appears to be a Crossplane package revision status.
+func (m Modules) IsPackageRevisionStatus() Matcher {
+	return m.IsTypeNamed(TypeSuffixPackageRevisionStatus, NamePackageRevisionStatus)
 }
 
 // IsProviderConfigSpec returns a Matcher that returns true if the supplied
 // field appears to be a Crossplane provider config spec.
-func IsProviderConfigSpec() Matcher {
-	return IsTypeNamed(TypeSuffixProviderConfigSpec, NameProviderConfigSpec)
+func (m Modules) IsProviderConfigSpec() Matcher {
+	return m.IsTypeNamed(TypeSuffixProviderConfigSpec, NameProviderConfigSpec)
 }
 
 // IsProviderConfigStatus returns a Matcher that returns true if the supplied
 // field appears to be a Crossplane provider config status.
-func IsProviderConfigStatus() Matcher {
-	return IsTypeNamed(TypeSuffixProviderConfigStatus, NameProviderConfigStatus)
+func (m Modules) IsProviderConfigStatus() Matcher {
+	return m.IsTypeNamed(TypeSuffixProviderConfigStatus, NameProviderConfigStatus)
 }
 
 // IsProviderConfigStatusCore returns a Matcher that returns true if the supplied
 // field appears to be a Crossplane provider config status from the core API v2
 // module.
-func IsProviderConfigStatusCore() Matcher {
-	return IsTypeNamed(TypeSuffixProviderConfigStatusCore, NameProviderConfigStatus)
+func (m Modules) IsProviderConfigStatusCore() Matcher {
+	return m.IsTypeNamed(TypeSuffixProviderConfigStatusCore, NameProviderConfigStatus)
 }
 
 // IsProviderConfigUsage returns a Matcher that returns true if the supplied
 // field appears to be a Crossplane provider config usage.
-func IsProviderConfigUsage() Matcher {
-	return IsTypeNamed(TypeSuffixProviderConfigUsage, NameProviderConfigUsage)
+func (m Modules) IsProviderConfigUsage() Matcher {
+	return m.IsTypeNamed(TypeSuffixProviderConfigUsage, NameProviderConfigUsage)
 }
 
 // IsProviderConfigUsageCore returns a Matcher that returns true if the supplied
 // field appears to be a Crossplane provider config usage from the core API v2
 // module.
-func IsProviderConfigUsageCore() Matcher {
-	return IsTypeNamed(TypeSuffixProviderConfigUsageCore, NameProviderConfigUsage)
+func (m Modules) IsProviderConfigUsageCore() Matcher {
+	return m.IsTypeNamed(TypeSuffixProviderConfigUsageCore, NameProviderConfigUsage)
 }
 
 // IsTypedProviderConfigUsage returns a Matcher that returns true if the supplied
 // field appears to be a Crossplane provider config usage with typed ref.
-func IsTypedProviderConfigUsage() Matcher {
-	return IsTypeNamed(TypeSuffixProviderConfigUsageV2, NameTypedProviderConfigUsage)
+func (m Modules) IsTypedProviderConfigUsage() Matcher {
+	return m.IsTypeNamed(TypeSuffixProviderConfigUsageV2, NameTypedProviderConfigUsage)
 }
 
 // IsTypedProviderConfigUsageCore returns a Matcher that returns true if the
 // supplied field appears to be a Crossplane provider config usage with typed ref
 // from the core API v2 module.
-func IsTypedProviderConfigUsageCore() Matcher {
-	return IsTypeNamed(TypeSuffixTypedProviderConfigUsageCore, NameTypedProviderConfigUsage)
+func (m Modules) IsTypedProviderConfigUsageCore() Matcher {
+	return m.IsTypeNamed(TypeSuffixTypedProviderConfigUsageCore, NameTypedProviderConfigUsage)
 }
 
 // IsItems returns a Matcher that returns true if the supplied field appears to
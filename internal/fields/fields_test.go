@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fields
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+// embedded returns an embedded field of a named struct type in the package
+// with the supplied path.
+func embedded(path, name string, complete bool) *types.Var {
+	p := types.NewPackage(path, "v1")
+	if complete {
+		p.MarkComplete()
+	}
+	n := types.NewNamed(types.NewTypeName(token.NoPos, p, name, nil), types.NewStruct(nil, nil), nil)
+	return types.NewField(token.NoPos, p, name, n, true)
+}
+
+func TestIsTypeNamed(t *testing.T) {
+	runtime := "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+
+	cases := map[string]struct {
+		reason string
+		m      Modules
+		f      *types.Var
+		is     func(Modules) Matcher
+		want   bool
+	}{
+		"Identical": {
+			reason: "A field of the well known type should match.",
+			f:      embedded(runtime, NameResourceSpec, true),
+			is:     Modules.IsResourceSpec,
+			want:   true,
+		},
+		"LookAlike": {
+			reason: "A field of a type whose path merely ends with the well known type's should not match.",
+			f:      embedded("example.org/github.com/crossplane/crossplane-runtime/v2/apis/common/v1", NameResourceSpec, true),
+			is:     Modules.IsResourceSpec,
+			want:   false,
+		},
+		"Fork": {
+			reason: "A field of a type from an accepted fork should match.",
+			m:      Modules{Forks: Forks{"github.com/crossplane/crossplane-runtime/v2": {"github.com/acme/crossplane-runtime/v2"}}},
+			f:      embedded("github.com/acme/crossplane-runtime/v2/apis/common/v1", NameResourceSpec, true),
+			is:     Modules.IsResourceSpec,
+			want:   true,
+		},
+		"UnacceptedFork": {
+			reason: "A field of a type from a fork that isn't accepted should not match.",
+			f:      embedded("github.com/acme/crossplane-runtime/v2/apis/common/v1", NameResourceSpec, true),
+			is:     Modules.IsResourceSpec,
+			want:   false,
+		},
+		"Remapped": {
+			reason: "A field of a type from the module a well known module is remapped to should match.",
+			m:      Modules{Remapped: map[string]string{"github.com/crossplane/crossplane-runtime/v2": "github.com/acme/runtime/v2"}},
+			f:      embedded("github.com/acme/runtime/v2/apis/common/v1", NameResourceSpec, true),
+			is:     Modules.IsResourceSpec,
+			want:   true,
+		},
+		"RemappedAway": {
+			reason: "A field of a type from a well known module that is remapped should not match.",
+			m:      Modules{Remapped: map[string]string{"github.com/crossplane/crossplane-runtime/v2": "github.com/acme/runtime/v2"}},
+			f:      embedded(runtime, NameResourceSpec, true),
+			is:     Modules.IsResourceSpec,
+			want:   false,
+		},
+		"PartialPathElement": {
+			reason: "Forks should only be accepted in place of whole path elements.",
+			m:      Modules{Forks: Forks{"github.com/crossplane/crossplane-run": {"github.com/acme/crossplane-run"}}},
+			f:      embedded("github.com/acme/crossplane-runtime/v2/apis/common/v1", NameResourceSpec, true),
+			is:     Modules.IsResourceSpec,
+			want:   false,
+		},
+		"NotLoaded": {
+			reason: "A field whose type's package wasn't loaded should be matched by suffix.",
+			f:      embedded("example.org/github.com/crossplane/crossplane-runtime/v2/apis/common/v1", NameResourceSpec, false),
+			is:     Modules.IsResourceSpec,
+			want:   true,
+		},
+		"Unqualified": {
+			reason: "A suffix without a package path should match any type with that suffix.",
+			f:      embedded("example.org/v1", "BucketSpec", true),
+			is:     func(m Modules) Matcher { return m.IsTypeNamed(TypeSuffixSpec, "BucketSpec") },
+			want:   true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.is(tc.m)(tc.f); got != tc.want {
+				t.Errorf("\n%s\nMatcher(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
@@ -19,15 +19,20 @@ package generate
 
 import (
 	"bytes"
+	"go/ast"
+	"go/format"
 	"go/parser"
 	"go/token"
-	"go/types"
+	"maps"
 	"os"
+	"strconv"
 
 	"github.com/dave/jennifer/jen"
 	"github.com/pkg/errors"
 	"golang.org/x/tools/go/packages"
 
+	"github.com/crossplane/crossplane-tools/internal/diagnostic"
+	"github.com/crossplane/crossplane-tools/internal/fields"
 	"github.com/crossplane/crossplane-tools/internal/match"
 	"github.com/crossplane/crossplane-tools/internal/method"
 )
@@ -37,9 +42,9 @@ import (
 const HeaderGenerated = "Code generated by angryjet. DO NOT EDIT."
 
 type options struct {
-	Matches       match.Object
 	ImportAliases map[string]string
 	Headers       []string
+	ModulePaths   map[string]string
 }
 
 // A WriteOption configures method generation behaviour.
@@ -54,31 +59,50 @@ func WithHeaders(h ...string) WriteOption {
 	}
 }
 
-// WithMatcher specifies an Object matcher that is used to filter the Objects
-// within the package down to the set that need the generated methods.
-func WithMatcher(m match.Object) WriteOption {
-	return func(o *options) {
-		o.Matches = m
-	}
-}
-
 // WithImportAliases configures a map of import paths to aliases that will be
 // used when generating code. For example if a generated method requires
 // "example.org/foo/bar" it may refer to that package as "foobar" by supplying
-// map[string]string{"example.org/foo/bar": "foobar"}.
+// map[string]string{"example.org/foo/bar": "foobar"}. Aliases supplied by
+// multiple calls are merged.
 func WithImportAliases(ia map[string]string) WriteOption {
 	return func(o *options) {
-		o.ImportAliases = ia
+		if o.ImportAliases == nil {
+			o.ImportAliases = map[string]string{}
+		}
+		maps.Copy(o.ImportAliases, ia)
 	}
 }
 
-// WriteMethods writes the supplied methods for each object in the supplied
-// package to the supplied file. Use WithMatcher to limit the objects for which
-// methods will be written. Methods will not be generated if a method with the
-// same name is already defined for the object outside of the supplied filename.
-// Files will not be written if they would contain no methods.
-func WriteMethods(p *packages.Package, ms method.Set, file string, wo ...WriteOption) error {
-	opts := &options{Matches: func(_ types.Object) bool { return true }}
+// WithModulePaths configures a map of module paths to the module paths that
+// generated code should import their packages from instead. For example
+// supplying map[string]string{"example.org/foo": "example.org/foo/v2"} makes
+// generated code that requires "example.org/foo/bar" import
+// "example.org/foo/v2/bar". Import aliases apply to either path.
+func WithModulePaths(m map[string]string) WriteOption {
+	return func(o *options) {
+		if o.ModulePaths == nil {
+			o.ModulePaths = map[string]string{}
+		}
+		maps.Copy(o.ModulePaths, m)
+	}
+}
+
+// A MatchedSet is a method set that is written for the objects its Matcher
+// matches.
+type MatchedSet struct {
+	Methods method.Set
+	Matcher match.Matcher
+}
+
+// Render returns the content of the supplied file, containing each of the
+// supplied method sets for the objects in the supplied package that they
+// match. Sets are rendered in order. Methods will not be rendered if a method
+// with the same name is already defined for the object outside of the supplied
+// file. Render returns nil if the file would contain no methods. Render
+// attempts to render methods for every object, returning a diagnostic.Errors
+// describing each object it could not render methods for.
+func Render(p *packages.Package, file string, sets []MatchedSet, wo ...WriteOption) ([]byte, error) {
+	opts := &options{}
 	for _, fn := range wo {
 		fn(opts)
 	}
@@ -94,6 +118,13 @@ func WriteMethods(p *packages.Package, ms method.Set, file string, wo ...WriteOp
 	for path, alias := range opts.ImportAliases {
 		f.ImportAlias(path, alias)
 	}
+	for from, to := range opts.ModulePaths {
+		for path, alias := range opts.ImportAliases {
+			if rest, ok := fields.InModule(path, to); ok {
+				f.ImportAlias(from+rest, alias)
+			}
+		}
+	}
 	for _, hc := range opts.Headers {
 		if hc != "" {
 			f.HeaderComment(hc)
@@ -101,24 +132,61 @@ func WriteMethods(p *packages.Package, ms method.Set, file string, wo ...WriteOp
 	}
 	f.HeaderComment(HeaderGenerated)
 
-	for _, n := range p.Types.Scope().Names() {
-		o := p.Types.Scope().Lookup(n)
-		if !opts.Matches(o) {
-			continue
+	filter := method.DefinedOutside(p.Fset, file)
+	errs := diagnostic.Errors{}
+	for _, s := range sets {
+		for _, n := range p.Types.Scope().Names() {
+			o := p.Types.Scope().Lookup(n)
+			if !s.Matcher.Match(o) {
+				continue
+			}
+			if err := s.Methods.Write(f, o, filter); err != nil {
+				errs = append(errs, diagnostic.At(p.Fset.Position(o.Pos()), errors.Wrapf(err, "cannot generate methods for %s", o.Name())))
+			}
 		}
-		ms.Write(f, o, method.DefinedOutside(p.Fset, file))
+	}
+	if len(errs) > 0 {
+		return nil, errs
 	}
 
 	b := &bytes.Buffer{}
 	if err := f.Render(b); err != nil {
-		return errors.Wrap(err, "cannot render Go file")
+		return nil, errors.Wrap(err, "cannot render Go file")
 	}
 
 	if ProducedNothing(b.Bytes()) {
-		return nil
+		return nil, nil
+	}
+
+	if len(opts.ModulePaths) == 0 {
+		return b.Bytes(), nil
 	}
+	out, err := remapImports(b.Bytes(), opts.ModulePaths)
+	return out, errors.Wrap(err, "cannot remap imports")
+}
 
-	return errors.Wrap(os.WriteFile(file, b.Bytes(), 0o644), "cannot write Go file") //nolint:gosec // We're comfortable with this being world readable.
+// remapImports rewrites the imports of the supplied Go source per the supplied
+// map of module paths. Jennifer names every import outside the standard
+// library, so code that refers to them is unchanged.
+func remapImports(src []byte, m map[string]string) ([]byte, error) {
+	fs := token.NewFileSet()
+	f, err := parser.ParseFile(fs, "f.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	for _, is := range f.Imports {
+		path, err := strconv.Unquote(is.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		is.Path.Value = strconv.Quote(fields.Remap(path, m))
+	}
+	ast.SortImports(fs, f)
+	b := &bytes.Buffer{}
+	if err := format.Node(b, fs, f); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
 }
 
 // ProducedNothing returns true if the supplied data is either not a valid Go
@@ -131,3 +199,31 @@ func ProducedNothing(data []byte) bool {
 	}
 	return len(f.Decls)+len(f.Scope.Objects) == 0
 }
+
+// IsGenerated returns true if the supplied file exists and was generated by
+// angryjet, i.e. has the HeaderGenerated comment before its package clause.
+// Files generated by other tools are not.
+func IsGenerated(file string) (bool, error) {
+	b, err := os.ReadFile(file) //nolint:gosec // Reading the supplied file is the point.
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "cannot read %s", file)
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), file, b, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return false, nil //nolint:nilerr // A file without a valid package clause wasn't generated.
+	}
+	for _, g := range f.Comments {
+		if g.Pos() > f.Package {
+			break
+		}
+		for _, c := range g.List {
+			if c.Text == "// "+HeaderGenerated {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
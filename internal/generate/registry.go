@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-tools/internal/comments"
+	"github.com/crossplane/crossplane-tools/internal/match"
+	"github.com/crossplane/crossplane-tools/internal/method"
+)
+
+// A Generator describes a method set that may be generated for one kind of
+// resource using one flavor of API types, for example managed resources that
+// use the crossplane-runtime common/v1 types.
+type Generator struct {
+	// Name uniquely identifies the generator, for example managed-legacy.
+	Name string
+
+	// Kind of resource the generator produces methods for, for example
+	// managed or providerconfig.
+	Kind string
+
+	// Flavor of API types the generator's resources are built from, for
+	// example legacy or modern-core.
+	Flavor string
+
+	// Scope of the resources the generator produces methods for, either
+	// match.ScopeCluster or match.ScopeNamespaced. A generator with no scope
+	// produces methods for resources of either scope. See match.Scope.
+	Scope string
+
+	// Output identifies the file the generator's methods are written to. See
+	// Filename.
+	Output string
+
+	// Receiver is the name of the receiver of generated methods.
+	Receiver string
+
+	// ImportAliases configures the aliases used by generated code. See
+	// WithImportAliases.
+	ImportAliases map[string]string
+
+	// Matcher determines which objects the generator produces methods for.
+	// Matchers that are also a match.Explainer can explain why an object does
+	// or does not match.
+	Matcher match.Matcher
+
+	// Methods returns the method set to generate for an object. The supplied
+	// comments are those of the package that contains the object.
+	Methods func(receiver string, c comments.Comments) method.Set
+}
+
+// Filename returns the default name of the file for the supplied output, for
+// example zz_generated.managed.go for the managed output.
+func Filename(output string) string {
+	return fmt.Sprintf("zz_generated.%s.go", output)
+}
+
+// A Registry of generators.
+type Registry struct {
+	generators []Generator
+	names      map[string]bool
+}
+
+// NewRegistry returns a registry of the supplied generators. It panics if the
+// generators are invalid; use Register to register generators that may be.
+func NewRegistry(g ...Generator) *Registry {
+	r := &Registry{names: map[string]bool{}}
+	if err := r.Register(g...); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Register the supplied generators. Generators are run in the order they are
+// registered. Generator names must be unique within a registry.
+func (r *Registry) Register(g ...Generator) error {
+	for _, gen := range g {
+		switch {
+		case gen.Name == "":
+			return errors.New("generator must have a name")
+		case r.names[gen.Name]:
+			return errors.Errorf("generator %q is already registered", gen.Name)
+		case gen.Output == "":
+			return errors.Errorf("generator %q must have an output", gen.Name)
+		case gen.Matcher == nil || gen.Methods == nil:
+			return errors.Errorf("generator %q must have a matcher and methods", gen.Name)
+		}
+		r.names[gen.Name] = true
+		r.generators = append(r.generators, gen)
+	}
+	return nil
+}
+
+// Generators returns the registered generators, in the order they were
+// registered.
+func (r *Registry) Generators() []Generator {
+	out := make([]Generator, len(r.generators))
+	copy(out, r.generators)
+	return out
+}
+
+// Get returns the named generator, if it is registered.
+func (r *Registry) Get(name string) (Generator, bool) {
+	for _, g := range r.generators {
+		if g.Name == name {
+			return g, true
+		}
+	}
+	return Generator{}, false
+}
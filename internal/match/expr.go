@@ -0,0 +1,186 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package match
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-tools/internal/comments"
+)
+
+// Functions that may be called in an expression.
+const (
+	// FnPath matches objects whose package import path matches a regular
+	// expression, e.g. path("/deprecated/").
+	FnPath = "path"
+
+	// FnName matches objects whose name matches a regular expression, e.g.
+	// name("^Cluster").
+	FnName = "name"
+
+	// FnMarker matches objects that have a comment marker, e.g.
+	// marker("kubebuilder:storageversion"). Markers with values are written
+	// key=value.
+	FnMarker = "marker"
+
+	// FnMatches matches objects that a named matcher matches, typically the
+	// matcher of a generator, e.g. matches("managed-legacy").
+	FnMatches = "matches"
+)
+
+// An Environment in which an expression is evaluated.
+type Environment struct {
+	// Comments from which comment markers are read.
+	Comments comments.Comments
+
+	// Matchers that may be referred to by name.
+	Matchers map[string]Matcher
+}
+
+// An Expression combines matchers using a small subset of Go expression
+// syntax: calls of the Fn functions with one string literal argument, the
+// operators &&, || and !, and parentheses. For example:
+//
+//	(matches("managed-legacy") || matches("managed-legacy-core")) && !path("/deprecated/")
+type Expression struct {
+	source string
+	root   ast.Expr
+}
+
+// ParseExpression parses the supplied expression. It returns an error if the
+// expression is malformed, calls an unknown function, or supplies an invalid
+// regular expression.
+func ParseExpression(s string) (*Expression, error) {
+	root, err := parser.ParseExpr(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot parse expression %q", s)
+	}
+	e := &Expression{source: s, root: root}
+	if _, err := e.compile(root, Environment{}, false); err != nil {
+		return nil, errors.Wrapf(err, "invalid expression %q", s)
+	}
+	return e, nil
+}
+
+// String returns the source of the expression.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Matcher returns a matcher that evaluates the expression in the supplied
+// environment. It returns an error if the expression refers to a matcher that
+// isn't in the environment.
+func (e *Expression) Matcher(env Environment) (Object, error) {
+	m, err := e.compile(e.root, env, true)
+	return m, errors.Wrapf(err, "invalid expression %q", e.source)
+}
+
+func (e *Expression) compile(x ast.Expr, env Environment, resolve bool) (Object, error) {
+	switch x := x.(type) {
+	case *ast.ParenExpr:
+		return e.compile(x.X, env, resolve)
+	case *ast.UnaryExpr:
+		if x.Op != token.NOT {
+			return nil, e.errorf(x, "unsupported operator %s", x.Op)
+		}
+		m, err := e.compile(x.X, env, resolve)
+		if err != nil {
+			return nil, err
+		}
+		return Not(m), nil
+	case *ast.BinaryExpr:
+		l, err := e.compile(x.X, env, resolve)
+		if err != nil {
+			return nil, err
+		}
+		r, err := e.compile(x.Y, env, resolve)
+		if err != nil {
+			return nil, err
+		}
+		if x.Op == token.LAND {
+			return AllOf(l, r), nil
+		}
+		if x.Op == token.LOR {
+			return AnyOf(l, r), nil
+		}
+		return nil, e.errorf(x, "unsupported operator %s", x.Op)
+	case *ast.CallExpr:
+		return e.call(x, env, resolve)
+	}
+	return nil, e.errorf(x, "unsupported expression %s", e.text(x))
+}
+
+func (e *Expression) call(x *ast.CallExpr, env Environment, resolve bool) (Object, error) {
+	fn, ok := x.Fun.(*ast.Ident)
+	if !ok {
+		return nil, e.errorf(x, "unsupported function %s", e.text(x.Fun))
+	}
+	if len(x.Args) != 1 {
+		return nil, e.errorf(x, "%s takes one string argument", fn.Name)
+	}
+	lit, ok := x.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return nil, e.errorf(x, "%s takes one string argument", fn.Name)
+	}
+	arg, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return nil, e.errorf(lit, "invalid string %s", lit.Value)
+	}
+
+	switch fn.Name {
+	case FnPath, FnName:
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, e.errorf(lit, "invalid regular expression: %s", err)
+		}
+		if fn.Name == FnPath {
+			return PackagePath(re), nil
+		}
+		return TypeName(re), nil
+	case FnMarker:
+		k, v, _ := strings.Cut(arg, "=")
+		return HasMarker(env.Comments, k, v), nil
+	case FnMatches:
+		if !resolve {
+			return Object(func(_ types.Object) bool { return false }), nil
+		}
+		m, ok := env.Matchers[arg]
+		if !ok {
+			return nil, e.errorf(lit, "unknown matcher %q", arg)
+		}
+		return m.Match, nil
+	}
+	return nil, e.errorf(fn, "unknown function %s", fn.Name)
+}
+
+// errorf returns an error prefixed with the column of the supplied node.
+func (e *Expression) errorf(n ast.Node, format string, args ...any) error {
+	return errors.Errorf("column %d: "+format, append([]any{int(n.Pos())}, args...)...)
+}
+
+// text returns the source of the supplied node.
+func (e *Expression) text(n ast.Node) string {
+	return e.source[n.Pos()-1 : n.End()-1]
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package match
+
+import (
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func TestExpression(t *testing.T) {
+	o := types.NewTypeName(token.NoPos, types.NewPackage("example.org/apis/deprecated/v1", "v1"), "ClusterWidget", nil)
+	env := Environment{Matchers: map[string]Matcher{
+		"always": Object(func(_ types.Object) bool { return true }),
+		"never":  Object(func(_ types.Object) bool { return false }),
+	}}
+
+	cases := map[string]struct {
+		reason string
+		expr   string
+		want   bool
+		err    string
+	}{
+		"Path": {
+			reason: "path should match the import path of the object's package.",
+			expr:   `path("/deprecated/")`,
+			want:   true,
+		},
+		"Name": {
+			reason: "name should match the name of the object.",
+			expr:   `name("^Namespaced")`,
+			want:   false,
+		},
+		"Matches": {
+			reason: "matches should use the named matcher.",
+			expr:   `matches("always")`,
+			want:   true,
+		},
+		"Operators": {
+			reason: "Operators should combine matchers with the usual precedence.",
+			expr:   `matches("never") || matches("always") && !(name("Widget$") && path("/v2$"))`,
+			want:   true,
+		},
+		"UnknownMatcher": {
+			reason: "Referring to a matcher that isn't in the environment should return an error.",
+			expr:   `matches("sometimes")`,
+			err:    `column 9: unknown matcher "sometimes"`,
+		},
+		"UnknownFunction": {
+			reason: "Calling an unknown function should return an error.",
+			expr:   `kind("Widget")`,
+			err:    "column 1: unknown function kind",
+		},
+		"InvalidRegexp": {
+			reason: "Supplying an invalid regular expression should return an error.",
+			expr:   `name("(")`,
+			err:    "column 6: invalid regular expression",
+		},
+		"UnsupportedOperator": {
+			reason: "Using an operator other than &&, || or ! should return an error.",
+			expr:   `name("a") == name("b")`,
+			err:    "column 1: unsupported operator ==",
+		},
+		"TooManyArguments": {
+			reason: "Calling a function with more than one argument should return an error.",
+			expr:   `name("a", "b")`,
+			err:    "name takes one string argument",
+		},
+		"Malformed": {
+			reason: "A malformed expression should return an error.",
+			expr:   `name("a") &&`,
+			err:    "cannot parse expression",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e, err := ParseExpression(tc.expr)
+			var m Object
+			if err == nil {
+				m, err = e.Matcher(env)
+			}
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("\n%s\nExpression(%q): want error containing %q, got %v", tc.reason, tc.expr, tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nExpression(%q): %v", tc.reason, tc.expr, err)
+			}
+			if got := m(o); got != tc.want {
+				t.Errorf("\n%s\nExpression(%q): want %t, got %t", tc.reason, tc.expr, tc.want, got)
+			}
+		})
+	}
+}
@@ -19,312 +19,325 @@ package match
 
 import (
 	"go/types"
+	"regexp"
 	"slices"
+	"strings"
 
 	"github.com/crossplane/crossplane-tools/internal/comments"
 	"github.com/crossplane/crossplane-tools/internal/fields"
 )
 
+// A Matcher determines whether an object matches.
+type Matcher interface {
+	Match(o types.Object) bool
+}
+
 // An Object matcher is a function that returns true if the supplied object
 // matches.
 type Object func(o types.Object) bool
 
-// ManagedLegacy returns an Object matcher that returns true if the supplied
-// Object is a legacy (cluster-scoped) Crossplane managed resource using
-// crossplane-runtime common/v1 types.
-func ManagedLegacy() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsObjectMeta().And(fields.IsEmbedded()),
-			fields.IsSpec().And(fields.HasFieldThat(
-				fields.IsResourceSpec().And(fields.IsEmbedded()),
-			)),
-			fields.IsStatus().And(fields.HasFieldThat(
-				fields.IsResourceStatus().And(fields.IsEmbedded()),
-			)),
-		)
+// Match returns true if the supplied object matches.
+func (fn Object) Match(o types.Object) bool {
+	return fn(o)
+}
+
+// A Modular matcher is a Structure whose well known types, like ResourceSpec,
+// are those of the supplied Modules. It matches as a Structure configured with
+// the zero Modules. Use ForModules to configure it.
+type Modular func(m fields.Modules) Structure
+
+// Match returns true if the supplied object matches.
+func (fn Modular) Match(o types.Object) bool {
+	return fn(fields.Modules{}).Match(o)
+}
+
+// Explain returns the result of each of the Structure's clauses.
+func (fn Modular) Explain(o types.Object) []Result {
+	return fn(fields.Modules{}).Explain(o)
+}
+
+// Reachable returns the result of each of the Structure's clauses, matched
+// against the supplied object's promoted fields.
+func (fn Modular) Reachable(o types.Object) []Result {
+	return fn(fields.Modules{}).Reachable(o)
+}
+
+// NearMiss returns a description of the clauses the supplied object is
+// missing, and true, if it nearly matches the Structure.
+func (fn Modular) NearMiss(o types.Object) (string, bool) {
+	return fn(fields.Modules{}).NearMiss(o)
+}
+
+// ForModules returns the supplied Matcher configured to match the well known
+// types of the supplied Modules. Matchers that aren't Modular are returned
+// unchanged.
+func ForModules(mt Matcher, m fields.Modules) Matcher {
+	if fn, ok := mt.(Modular); ok {
+		return fn(m)
 	}
+	return mt
 }
 
-// ManagedModern returns an Object matcher that returns true if the supplied
-// Object is a modern (namespaced) Crossplane managed resource using
-// crossplane-runtime common/v2 types.
-func ManagedModern() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsObjectMeta().And(fields.IsEmbedded()),
-			fields.IsSpec().And(fields.HasFieldThat(
-				fields.IsResourceV2Spec().And(fields.IsEmbedded()),
-			)),
-			fields.IsStatus().And(fields.HasFieldThat(
-				fields.IsResourceStatus().And(fields.IsEmbedded()),
-			)),
+// typeMeta returns a clause that matches an embedded TypeMeta.
+func typeMeta(m fields.Modules) Clause {
+	return generic(embeds(fields.TypeSuffixTypeMeta, m.IsTypeMeta()))
+}
+
+// objectMeta returns a clause that matches an embedded ObjectMeta.
+func objectMeta(m fields.Modules) Clause {
+	return generic(embeds(fields.TypeSuffixObjectMeta, m.IsObjectMeta()))
+}
+
+// object returns the clauses of a Kubernetes object whose spec and status
+// satisfy the supplied clauses.
+func object(m fields.Modules, spec, status Clause) []Clause {
+	return []Clause{
+		typeMeta(m),
+		objectMeta(m),
+		field(fields.NameSpec, m.IsSpec(), spec),
+		field(fields.NameStatus, m.IsStatus(), status),
+	}
+}
+
+// list returns a Structure that matches a list of items with the supplied
+// clauses.
+func list(m fields.Modules, items ...Clause) Structure {
+	return Structure{
+		typeMeta(m),
+		Clause{Description: "slice field " + fields.NameItems, Field: fields.IsItems().And(fields.IsSlice()), Fields: items},
+	}
+}
+
+// ManagedLegacy returns a matcher that matches a legacy (cluster-scoped)
+// Crossplane managed resource using crossplane-runtime common/v1 types.
+func ManagedLegacy() Modular {
+	return func(m fields.Modules) Structure {
+		return object(m,
+			embeds(fields.TypeSuffixResourceSpec, m.IsResourceSpec()),
+			embeds(fields.TypeSuffixResourceStatus, m.IsResourceStatus()),
 		)
 	}
 }
 
-// ManagedModernCore returns an Object matcher that returns true if the supplied
-// Object is a namespaced (modern) Crossplane managed resource using the core API
-// v2 types.
-func ManagedModernCore() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsObjectMeta().And(fields.IsEmbedded()),
-			fields.IsSpec().And(fields.HasFieldThat(
-				fields.IsManagedResourceSpecCore().And(fields.IsEmbedded()),
-			)),
-			fields.IsStatus().And(fields.HasFieldThat(
-				fields.IsManagedResourceStatusCore().And(fields.IsEmbedded()),
-			)),
+// ManagedModern returns a matcher that matches a modern (namespaced)
+// Crossplane managed resource using crossplane-runtime common/v2 types.
+func ManagedModern() Modular {
+	return func(m fields.Modules) Structure {
+		return object(m,
+			embeds(fields.TypeSuffixResourceV2Spec, m.IsResourceV2Spec()),
+			embeds(fields.TypeSuffixResourceStatus, m.IsResourceStatus()),
 		)
 	}
 }
 
-// ManagedListLegacy returns an Object matcher that returns true if the supplied
-// Object is a list of legacy (cluster-scoped) Crossplane managed resources.
-func ManagedListLegacy() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsItems().And(fields.IsSlice()).And(fields.HasFieldThat(
-				fields.IsTypeMeta().And(fields.IsEmbedded()),
-				fields.IsObjectMeta().And(fields.IsEmbedded()),
-				fields.IsSpec().And(fields.HasFieldThat(
-					fields.IsResourceSpec().And(fields.IsEmbedded()),
-				)),
-				fields.IsStatus().And(fields.HasFieldThat(
-					fields.IsResourceStatus().And(fields.IsEmbedded()),
-				)),
-			)),
+// ManagedModernCore returns a matcher that matches a namespaced (modern)
+// Crossplane managed resource using the core API v2 types.
+func ManagedModernCore() Modular {
+	return func(m fields.Modules) Structure {
+		return object(m,
+			embeds(fields.TypeSuffixManagedResourceSpecCore, m.IsManagedResourceSpecCore()),
+			embeds(fields.TypeSuffixManagedResourceStatusCore, m.IsManagedResourceStatusCore()),
 		)
 	}
 }
 
-// ManagedListModern returns an Object matcher that returns true if the supplied
-// Object is a list of modern (namespaced) Crossplane managed resources using
-// crossplane-runtime common/v2 types.
-func ManagedListModern() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsItems().And(fields.IsSlice()).And(fields.HasFieldThat(
-				fields.IsTypeMeta().And(fields.IsEmbedded()),
-				fields.IsObjectMeta().And(fields.IsEmbedded()),
-				fields.IsSpec().And(fields.HasFieldThat(
-					fields.IsResourceV2Spec().And(fields.IsEmbedded()),
-				)),
-				fields.IsStatus().And(fields.HasFieldThat(
-					fields.IsResourceStatus().And(fields.IsEmbedded()),
-				)),
-			)),
+// ManagedLegacyCore returns a matcher that matches a cluster-scoped (legacy)
+// Crossplane managed resource using the core API v2 ClusterManagedResourceSpec.
+func ManagedLegacyCore() Modular {
+	return func(m fields.Modules) Structure {
+		return object(m,
+			embeds(fields.TypeSuffixClusterManagedResourceSpecCore, m.IsClusterManagedResourceSpecCore()),
+			embeds(fields.TypeSuffixResourceStatus+" or "+fields.TypeSuffixManagedResourceStatusCore, m.IsResourceStatus().Or(m.IsManagedResourceStatusCore())),
 		)
 	}
 }
 
-// ManagedListModernCore returns an Object matcher that returns true if the
-// supplied Object is a list of namespaced (modern) Crossplane managed resources
+// ManagedListLegacy returns a matcher that matches a list of legacy
+// (cluster-scoped) Crossplane managed resources.
+func ManagedListLegacy() Modular {
+	return func(m fields.Modules) Structure {
+		return list(m, ManagedLegacy()(m)...)
+	}
+}
+
+// ManagedListModern returns a matcher that matches a list of modern
+// (namespaced) Crossplane managed resources using crossplane-runtime common/v2
+// types.
+func ManagedListModern() Modular {
+	return func(m fields.Modules) Structure {
+		return list(m, ManagedModern()(m)...)
+	}
+}
+
+// ManagedListModernCore returns a matcher that matches a list of namespaced
+// (modern) Crossplane managed resources using the core API v2 types.
+func ManagedListModernCore() Modular {
+	return func(m fields.Modules) Structure {
+		return list(m, ManagedModernCore()(m)...)
+	}
+}
+
+// ManagedListLegacyCore returns a matcher that matches a list of
+// cluster-scoped (legacy) Crossplane managed resources using the core API v2
+// types.
+func ManagedListLegacyCore() Modular {
+	return func(m fields.Modules) Structure {
+		return list(m, ManagedLegacyCore()(m)...)
+	}
+}
+
+// CompositeCore returns a matcher that matches a Crossplane composite resource
 // using the core API v2 types.
-func ManagedListModernCore() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsItems().And(fields.IsSlice()).And(fields.HasFieldThat(
-				fields.IsTypeMeta().And(fields.IsEmbedded()),
-				fields.IsObjectMeta().And(fields.IsEmbedded()),
-				fields.IsSpec().And(fields.HasFieldThat(
-					fields.IsManagedResourceSpecCore().And(fields.IsEmbedded()),
-				)),
-				fields.IsStatus().And(fields.HasFieldThat(
-					fields.IsManagedResourceStatusCore().And(fields.IsEmbedded()),
-				)),
-			)),
+func CompositeCore() Modular {
+	return func(m fields.Modules) Structure {
+		return object(m,
+			embeds(fields.TypeSuffixCompositeResourceSpecCore, m.IsCompositeResourceSpecCore()),
+			embeds(fields.TypeSuffixCompositeResourceStatusCore, m.IsCompositeResourceStatusCore()),
 		)
 	}
 }
 
-// ManagedLegacyCore returns an Object matcher that returns true if the supplied
-// Object is a cluster-scoped (legacy) Crossplane managed resource using the core
-// API v2 ClusterManagedResourceSpec.
-func ManagedLegacyCore() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsObjectMeta().And(fields.IsEmbedded()),
-			fields.IsSpec().And(fields.HasFieldThat(
-				fields.IsClusterManagedResourceSpecCore().And(fields.IsEmbedded()),
-			)),
-			fields.IsStatus().And(fields.HasFieldThat(
-				fields.IsResourceStatus().Or(fields.IsManagedResourceStatusCore()).And(fields.IsEmbedded()),
-			)),
+// CompositeClaimCore returns a matcher that matches a Crossplane composite
+// resource claim using the core API v2 types.
+func CompositeClaimCore() Modular {
+	return func(m fields.Modules) Structure {
+		return object(m,
+			embeds(fields.TypeSuffixCompositeClaimSpecCore, m.IsCompositeClaimSpecCore()),
+			embeds(fields.TypeSuffixCompositeClaimStatusCore, m.IsCompositeClaimStatusCore()),
 		)
 	}
 }
 
-// ManagedListLegacyCore returns an Object matcher that returns true if the
-// supplied Object is a list of cluster-scoped (legacy) Crossplane managed
-// resources using the core API v2 types.
-func ManagedListLegacyCore() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsItems().And(fields.IsSlice()).And(fields.HasFieldThat(
-				fields.IsTypeMeta().And(fields.IsEmbedded()),
-				fields.IsObjectMeta().And(fields.IsEmbedded()),
-				fields.IsSpec().And(fields.HasFieldThat(
-					fields.IsClusterManagedResourceSpecCore().And(fields.IsEmbedded()),
-				)),
-				fields.IsStatus().And(fields.HasFieldThat(
-					fields.IsResourceStatus().Or(fields.IsManagedResourceStatusCore()).And(fields.IsEmbedded()),
-				)),
-			)),
+// Package returns a matcher that matches a Crossplane package, such as a
+// Provider, Function or Configuration.
+func Package() Modular {
+	return func(m fields.Modules) Structure {
+		return object(m,
+			embeds(fields.TypeSuffixPackageSpec, m.IsPackageSpec()),
+			embeds(fields.TypeSuffixPackageStatus, m.IsPackageStatus()),
 		)
 	}
 }
 
-// ProviderConfig returns an Object matcher that returns true if the supplied
-// Object is a Crossplane ProviderConfig.
-func ProviderConfig() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsObjectMeta().And(fields.IsEmbedded()),
-			fields.IsSpec(),
-			fields.IsStatus().And(fields.HasFieldThat(
-				fields.IsProviderConfigStatus().And(fields.IsEmbedded()),
-			)),
+// PackageRevision returns a matcher that matches a Crossplane package
+// revision, such as a ProviderRevision.
+func PackageRevision() Modular {
+	return func(m fields.Modules) Structure {
+		return object(m,
+			embeds(fields.TypeSuffixPackageRevisionSpec, m.IsPackageRevisionSpec()),
+			embeds(fields.TypeSuffixPackageRevisionStatus, m.IsPackageRevisionStatus()),
 		)
 	}
 }
 
-// ProviderConfigCore returns an Object matcher that returns true if the supplied
-// Object is a Crossplane ProviderConfig using the core API v2 types.
-func ProviderConfigCore() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsObjectMeta().And(fields.IsEmbedded()),
-			fields.IsSpec(),
-			fields.IsStatus().And(fields.HasFieldThat(
-				fields.IsProviderConfigStatusCore().And(fields.IsEmbedded()),
-			)),
-		)
+// ProviderConfig returns a matcher that matches a Crossplane ProviderConfig.
+func ProviderConfig() Modular {
+	return func(m fields.Modules) Structure {
+		return Structure{
+			typeMeta(m),
+			objectMeta(m),
+			field(fields.NameSpec, m.IsSpec()),
+			field(fields.NameStatus, m.IsStatus(),
+				embeds(fields.TypeSuffixProviderConfigStatus, m.IsProviderConfigStatus()),
+			),
+		}
 	}
 }
 
-// ProviderConfigUsageLegacy returns an Object matcher that returns true if the
-// supplied Object is a legacy (non-typed) Crossplane ProviderConfigUsage.
-func ProviderConfigUsageLegacy() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsObjectMeta().And(fields.IsEmbedded()),
-			fields.IsProviderConfigUsage().And(fields.IsEmbedded()),
-		)
+// ProviderConfigCore returns a matcher that matches a Crossplane
+// ProviderConfig using the core API v2 types.
+func ProviderConfigCore() Modular {
+	return func(m fields.Modules) Structure {
+		return Structure{
+			typeMeta(m),
+			objectMeta(m),
+			field(fields.NameSpec, m.IsSpec()),
+			field(fields.NameStatus, m.IsStatus(),
+				embeds(fields.TypeSuffixProviderConfigStatusCore, m.IsProviderConfigStatusCore()),
+			),
+		}
 	}
 }
 
-// ProviderConfigUsageModern returns an Object matcher that returns true if the
-// supplied Object is a modern (typed) Crossplane ProviderConfigUsage.
-func ProviderConfigUsageModern() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsObjectMeta().And(fields.IsEmbedded()),
-			fields.IsTypedProviderConfigUsage().And(fields.IsEmbedded()),
-		)
+// ProviderConfigUsageLegacy returns a matcher that matches a legacy
+// (non-typed) Crossplane ProviderConfigUsage.
+func ProviderConfigUsageLegacy() Modular {
+	return func(m fields.Modules) Structure {
+		return Structure{
+			typeMeta(m),
+			objectMeta(m),
+			embeds(fields.TypeSuffixProviderConfigUsage, m.IsProviderConfigUsage()),
+		}
 	}
 }
 
-// ProviderConfigUsageLegacyCore returns an Object matcher that returns true if
-// the supplied Object is a Crossplane ProviderConfigUsage embedding the core API
-// v2 non-typed ProviderConfigUsage.
-func ProviderConfigUsageLegacyCore() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsObjectMeta().And(fields.IsEmbedded()),
-			fields.IsProviderConfigUsageCore().And(fields.IsEmbedded()),
-		)
+// ProviderConfigUsageModern returns a matcher that matches a modern (typed)
+// Crossplane ProviderConfigUsage.
+func ProviderConfigUsageModern() Modular {
+	return func(m fields.Modules) Structure {
+		return Structure{
+			typeMeta(m),
+			objectMeta(m),
+			embeds(fields.TypeSuffixProviderConfigUsageV2, m.IsTypedProviderConfigUsage()),
+		}
 	}
 }
 
-// ProviderConfigUsageModernCore returns an Object matcher that returns true if
-// the supplied Object is a Crossplane ProviderConfigUsage embedding the core API
-// v2 TypedProviderConfigUsage.
-func ProviderConfigUsageModernCore() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsObjectMeta().And(fields.IsEmbedded()),
-			fields.IsTypedProviderConfigUsageCore().And(fields.IsEmbedded()),
-		)
+// ProviderConfigUsageLegacyCore returns a matcher that matches a Crossplane
+// ProviderConfigUsage embedding the core API v2 non-typed ProviderConfigUsage.
+func ProviderConfigUsageLegacyCore() Modular {
+	return func(m fields.Modules) Structure {
+		return Structure{
+			typeMeta(m),
+			objectMeta(m),
+			embeds(fields.TypeSuffixProviderConfigUsageCore, m.IsProviderConfigUsageCore()),
+		}
 	}
 }
 
-// ProviderConfigUsageListLegacy returns an Object matcher that returns true if
-// the supplied Object is a list of legacy (non-typed) Crossplane provider config
-// usages.
-func ProviderConfigUsageListLegacy() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsItems().And(fields.IsSlice()).And(fields.HasFieldThat(
-				fields.IsTypeMeta().And(fields.IsEmbedded()),
-				fields.IsObjectMeta().And(fields.IsEmbedded()),
-				fields.IsProviderConfigUsage().And(fields.IsEmbedded()),
-			)),
-		)
+// ProviderConfigUsageModernCore returns a matcher that matches a Crossplane
+// ProviderConfigUsage embedding the core API v2 TypedProviderConfigUsage.
+func ProviderConfigUsageModernCore() Modular {
+	return func(m fields.Modules) Structure {
+		return Structure{
+			typeMeta(m),
+			objectMeta(m),
+			embeds(fields.TypeSuffixTypedProviderConfigUsageCore, m.IsTypedProviderConfigUsageCore()),
+		}
 	}
 }
 
-// ProviderConfigUsageListModern returns an Object matcher that returns true if
-// the supplied Object is a list of modern (typed) Crossplane provider config
-// usages.
-func ProviderConfigUsageListModern() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsItems().And(fields.IsSlice()).And(fields.HasFieldThat(
-				fields.IsTypeMeta().And(fields.IsEmbedded()),
-				fields.IsObjectMeta().And(fields.IsEmbedded()),
-				fields.IsTypedProviderConfigUsage().And(fields.IsEmbedded()),
-			)),
-		)
+// ProviderConfigUsageListLegacy returns a matcher that matches a list of
+// legacy (non-typed) Crossplane provider config usages.
+func ProviderConfigUsageListLegacy() Modular {
+	return func(m fields.Modules) Structure {
+		return list(m, ProviderConfigUsageLegacy()(m)...)
 	}
 }
 
-// ProviderConfigUsageListLegacyCore returns an Object matcher that returns true
-// if the supplied Object is a list of Crossplane provider config usages
-// embedding the core API v2 non-typed ProviderConfigUsage.
-func ProviderConfigUsageListLegacyCore() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsItems().And(fields.IsSlice()).And(fields.HasFieldThat(
-				fields.IsTypeMeta().And(fields.IsEmbedded()),
-				fields.IsObjectMeta().And(fields.IsEmbedded()),
-				fields.IsProviderConfigUsageCore().And(fields.IsEmbedded()),
-			)),
-		)
+// ProviderConfigUsageListModern returns a matcher that matches a list of
+// modern (typed) Crossplane provider config usages.
+func ProviderConfigUsageListModern() Modular {
+	return func(m fields.Modules) Structure {
+		return list(m, ProviderConfigUsageModern()(m)...)
 	}
 }
 
-// ProviderConfigUsageListModernCore returns an Object matcher that returns true
-// if the supplied Object is a list of Crossplane provider config usages
-// embedding the core API v2 TypedProviderConfigUsage.
-func ProviderConfigUsageListModernCore() Object {
-	return func(o types.Object) bool {
-		return fields.Has(o,
-			fields.IsTypeMeta().And(fields.IsEmbedded()),
-			fields.IsItems().And(fields.IsSlice()).And(fields.HasFieldThat(
-				fields.IsTypeMeta().And(fields.IsEmbedded()),
-				fields.IsObjectMeta().And(fields.IsEmbedded()),
-				fields.IsTypedProviderConfigUsageCore().And(fields.IsEmbedded()),
-			)),
-		)
+// ProviderConfigUsageListLegacyCore returns a matcher that matches a list of
+// Crossplane provider config usages embedding the core API v2 non-typed
+// ProviderConfigUsage.
+func ProviderConfigUsageListLegacyCore() Modular {
+	return func(m fields.Modules) Structure {
+		return list(m, ProviderConfigUsageLegacyCore()(m)...)
+	}
+}
+
+// ProviderConfigUsageListModernCore returns a matcher that matches a list of
+// Crossplane provider config usages embedding the core API v2
+// TypedProviderConfigUsage.
+func ProviderConfigUsageListModernCore() Modular {
+	return func(m fields.Modules) Structure {
+		return list(m, ProviderConfigUsageModernCore()(m)...)
 	}
 }
 
@@ -354,12 +367,80 @@ func DoesNotHaveMarker(c comments.Comments, k, v string) Object {
 	}
 }
 
+// Scopes of Kubernetes resources.
+const (
+	ScopeCluster    = "Cluster"
+	ScopeNamespaced = "Namespaced"
+)
+
+// resourceMarker is the kubebuilder comment marker whose arguments include a
+// resource's scope, e.g. +kubebuilder:resource:scope=Cluster,categories=crossplane.
+const resourceMarker = "kubebuilder:resource:"
+
+// Scope returns the scope of the supplied Object. The scope is read from the
+// Object's +kubebuilder:resource:scope comment marker if it has one. Otherwise
+// Objects whose name begins with Cluster, like ClusterProviderConfig, are
+// cluster scoped and all others are namespaced, per kubebuilder's default.
+func Scope(c comments.Comments, o types.Object) string {
+	for _, comment := range []string{c.For(o), c.Before(o)} {
+		for k, vs := range comments.ParseMarkers(comment) {
+			if !strings.HasPrefix(k, resourceMarker) {
+				continue
+			}
+			for _, v := range vs {
+				for arg := range strings.SplitSeq(strings.TrimPrefix(k, resourceMarker)+"="+v, ",") {
+					if name, scope, ok := strings.Cut(arg, "="); ok && name == "scope" {
+						return scope
+					}
+				}
+			}
+		}
+	}
+	if strings.HasPrefix(o.Name(), ScopeCluster) {
+		return ScopeCluster
+	}
+	return ScopeNamespaced
+}
+
+// InScope returns an Object matcher that returns true if the supplied Object
+// has the supplied scope. Comment markers are read from the supplied Comments.
+// See Scope.
+func InScope(c comments.Comments, scope string) Object {
+	return func(o types.Object) bool {
+		return Scope(c, o) == scope
+	}
+}
+
+// Not returns an Object matcher that returns true if the supplied matcher does
+// not match.
+func Not(m Matcher) Object {
+	return func(o types.Object) bool {
+		return !m.Match(o)
+	}
+}
+
+// PackagePath returns an Object matcher that returns true if the import path of
+// the supplied Object's package matches the supplied regular expression.
+func PackagePath(re *regexp.Regexp) Object {
+	return func(o types.Object) bool {
+		return o.Pkg() != nil && re.MatchString(o.Pkg().Path())
+	}
+}
+
+// TypeName returns an Object matcher that returns true if the name of the
+// supplied Object matches the supplied regular expression.
+func TypeName(re *regexp.Regexp) Object {
+	return func(o types.Object) bool {
+		return re.MatchString(o.Name())
+	}
+}
+
 // AllOf returns an Object matcher that returns true if all of the supplied
-// Object matchers return true.
-func AllOf(match ...Object) Object {
+// matchers match.
+func AllOf(match ...Matcher) Object {
 	return func(o types.Object) bool {
-		for _, fn := range match {
-			if !fn(o) {
+		for _, m := range match {
+			if !m.Match(o) {
 				return false
 			}
 		}
@@ -368,11 +449,11 @@ func AllOf(match ...Object) Object {
 }
 
 // AnyOf returns an Object matcher that returns true if any of the supplied
-// Object matchers return true.
-func AnyOf(match ...Object) Object {
+// matchers match.
+func AnyOf(match ...Matcher) Object {
 	return func(o types.Object) bool {
-		for _, fn := range match {
-			if fn(o) {
+		for _, m := range match {
+			if m.Match(o) {
 				return true
 			}
 		}
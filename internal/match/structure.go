@@ -0,0 +1,233 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package match
+
+import (
+	"go/types"
+	"iter"
+
+	"github.com/crossplane/crossplane-tools/internal/fields"
+)
+
+// A Clause of a Structure. An object satisfies a clause if it has a field that
+// matches the clause's Field matcher, and whose type satisfies the clause's
+// Fields. The clause's Fields may be satisfied by fields promoted from structs
+// embedded at any depth, for example a Spec may embed a CommonSpec that embeds
+// a ResourceSpec.
+type Clause struct {
+	// Description of the field the clause requires, for example "embedded
+	// k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta".
+	Description string
+
+	// Field that must exist.
+	Field fields.Matcher
+
+	// Fields that the type of the field must have, if any.
+	Fields []Clause
+
+	// Generic clauses, like embedding TypeMeta, are satisfied by many types.
+	// Satisfying them doesn't suggest that a type was meant to match.
+	Generic bool
+}
+
+// matcher returns a field Matcher equivalent to the clause.
+func (c Clause) matcher() fields.Matcher {
+	if len(c.Fields) == 0 {
+		return c.Field
+	}
+	return c.Field.And(fields.HasPromotedFieldThat(matchers(c.Fields)...))
+}
+
+func matchers(cs []Clause) []fields.Matcher {
+	m := make([]fields.Matcher, len(cs))
+	for i, c := range cs {
+		m[i] = c.matcher()
+	}
+	return m
+}
+
+// A Structure matcher matches objects that satisfy all of its clauses. Unlike
+// an Object matcher a Structure can explain why an object does or does not
+// match.
+type Structure []Clause
+
+// Match returns true if the supplied object satisfies all clauses.
+func (s Structure) Match(o types.Object) bool {
+	return fields.Has(o, matchers(s)...)
+}
+
+// Explain returns the result of each clause for the supplied object.
+func (s Structure) Explain(o types.Object) []Result {
+	return explain(fields.Struct(o), s)
+}
+
+// NearMiss returns a description of the only clause the supplied object does
+// not satisfy, if the object satisfies all other clauses and at least one of
+// them is specific to this structure. It returns false if the object matches,
+// or if it is not close to matching.
+func (s Structure) NearMiss(o types.Object) (string, bool) {
+	r := s.Explain(o)
+	missing := Missing(r)
+	if len(missing) != 1 || !specific(s, r) {
+		return "", false
+	}
+	return missing[0], true
+}
+
+// specific returns true if any leaf clause that is not generic was satisfied.
+func specific(cs []Clause, r []Result) bool {
+	for i, c := range cs {
+		switch {
+		case len(r[i].Fields) > 0:
+			if specific(c.Fields, r[i].Fields) {
+				return true
+			}
+		case r[i].Matched && !c.Generic && len(c.Fields) == 0:
+			return true
+		}
+	}
+	return false
+}
+
+// Reachable returns the result of each clause for the supplied object, like
+// Explain, except that the top level clauses may also be satisfied by fields
+// promoted from the object's embedded structs.
+func (s Structure) Reachable(o types.Object) []Result {
+	return explainFields(fields.Struct(o), s, fields.Promoted)
+}
+
+// A Result of evaluating a Clause against an object.
+type Result struct {
+	// Clause that was evaluated.
+	Clause string
+
+	// Matched is true if the object satisfies the clause.
+	Matched bool
+
+	// Fields are the results of the clause's Fields, evaluated against the
+	// field that best matched the clause. Fields is empty if no field matched
+	// the clause's Field matcher.
+	Fields []Result
+}
+
+// Missing returns a description of each leaf clause that was not satisfied,
+// prefixed by the descriptions of its parent clauses.
+func Missing(r []Result) []string {
+	return missing(r, "")
+}
+
+func missing(r []Result, prefix string) []string {
+	out := make([]string, 0)
+	for _, rs := range r {
+		switch {
+		case rs.Matched:
+		case len(rs.Fields) > 0:
+			out = append(out, missing(rs.Fields, prefix+rs.Clause+": ")...)
+		default:
+			out = append(out, prefix+rs.Clause)
+		}
+	}
+	return out
+}
+
+// Distance returns the number of clauses, including nested clauses, that did
+// not match. A distance of zero means the object matched.
+func Distance(r []Result) int {
+	d := 0
+	for _, rs := range r {
+		switch {
+		case rs.Matched:
+		case len(rs.Fields) > 0:
+			d += Distance(rs.Fields)
+		default:
+			d++
+		}
+	}
+	return d
+}
+
+// explain the supplied clauses against the fields of the supplied struct. The
+// clauses' Fields are explained against promoted fields.
+func explain(s *types.Struct, cs []Clause) []Result {
+	return explainFields(s, cs, (*types.Struct).Fields)
+}
+
+// explainFields explains the supplied clauses against the fields of the
+// supplied struct returned by fieldsOf.
+func explainFields(s *types.Struct, cs []Clause, fieldsOf func(*types.Struct) iter.Seq[*types.Var]) []Result {
+	r := make([]Result, len(cs))
+	for i, c := range cs {
+		r[i] = Result{Clause: c.Description}
+		if s == nil {
+			continue
+		}
+		for f := range fieldsOf(s) {
+			if !c.Field(f) {
+				continue
+			}
+			if len(c.Fields) == 0 {
+				r[i].Matched = true
+				break
+			}
+			fr := explainFields(fields.Struct(f), c.Fields, fields.Promoted)
+			if Distance(fr) == 0 {
+				r[i] = Result{Clause: c.Description, Matched: true, Fields: fr}
+				break
+			}
+			// Explain using the first candidate field, unless a later one
+			// matches.
+			if r[i].Fields == nil {
+				r[i].Fields = fr
+			}
+		}
+	}
+	return r
+}
+
+// An Explainer can explain why an object does or does not match.
+type Explainer interface {
+	Explain(o types.Object) []Result
+}
+
+// A Reacher can explain whether the fields a matcher requires are reachable
+// from an object, even if they are promoted from embedded structs.
+type Reacher interface {
+	Reachable(o types.Object) []Result
+}
+
+// A NearMisser can describe what an object that nearly matches is missing.
+type NearMisser interface {
+	NearMiss(o types.Object) (string, bool)
+}
+
+// embeds returns a Clause that requires an embedded field of the supplied
+// type.
+func embeds(typ string, m fields.Matcher) Clause {
+	return Clause{Description: "embedded " + typ, Field: m.And(fields.IsEmbedded())}
+}
+
+// field returns a Clause that requires a field with the supplied name. A field
+// without clauses of its own is generic.
+func field(name string, m fields.Matcher, cs ...Clause) Clause {
+	return Clause{Description: "field " + name, Field: m, Fields: cs, Generic: len(cs) == 0}
+}
+
+// generic returns a copy of the supplied Clause that is generic.
+func generic(c Clause) Clause {
+	c.Generic = true
+	return c
+}
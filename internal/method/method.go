@@ -21,23 +21,27 @@ import (
 	"go/token"
 	"go/types"
 	"sort"
+	"strings"
 
 	"github.com/dave/jennifer/jen"
+	"github.com/pkg/errors"
 
 	"github.com/crossplane/crossplane-tools/internal/fields"
 )
 
 // New is a function that adds a method on the supplied object in the
-// supplied file.
-type New func(f *jen.File, o types.Object)
+// supplied file. It returns an error if the method cannot be written, for
+// example because the object has a malformed comment marker.
+type New func(f *jen.File, o types.Object) error
 
 // A Set is a map of method names to the New functions that produce
 // them.
 type Set map[string]New
 
 // Write the method Set for the supplied Object to the supplied file. Methods
-// are filtered by the supplied Filter.
-func (s Set) Write(f *jen.File, o types.Object, mf Filter) {
+// are filtered by the supplied Filter. Write stops at the first method that
+// cannot be written.
+func (s Set) Write(f *jen.File, o types.Object, mf Filter) error {
 	names := make([]string, 0, len(s))
 	for name := range s {
 		names = append(names, name)
@@ -48,8 +52,35 @@ func (s Set) Write(f *jen.File, o types.Object, mf Filter) {
 		if mf(o, name) {
 			continue
 		}
-		s[name](f, o)
+		if err := s[name](f, o); err != nil {
+			return errors.Wrapf(err, "cannot write method %s", name)
+		}
+	}
+	return nil
+}
+
+// selector returns an expression that selects the supplied path of fields or
+// methods from the supplied receiver, for example mg.Spec.ProviderConfigReference.
+// Each field or method may be promoted from a struct embedded at any depth, in
+// which case it is selected directly. It returns an error if a field or method
+// does not exist, or is ambiguous because it is promoted from more than one
+// struct at the same depth.
+func selector(o types.Object, receiver string, path ...string) (*jen.Statement, error) {
+	s := jen.Id(receiver)
+	t := o.Type()
+	for i, name := range path {
+		obj, index, _ := types.LookupFieldOrMethod(t, true, nil, name)
+		if obj == nil {
+			at := strings.Join(append([]string{o.Name()}, path[:i]...), ".")
+			if index != nil {
+				return nil, errors.Errorf("%s.%s is ambiguous; it is promoted from more than one embedded struct", at, name)
+			}
+			return nil, errors.Errorf("%s has no field or method %s", at, name)
+		}
+		s = s.Dot(name)
+		t = obj.Type()
 	}
+	return s, nil
 }
 
 // A Filter is a function that determines whether a method should be written for
@@ -76,91 +107,143 @@ func DefinedOutside(fs *token.FileSet, filename string) Filter {
 	}
 }
 
+// DefinedAt returns the position at which the supplied object's method with
+// the supplied name is defined, if it is defined.
+func DefinedAt(fs *token.FileSet, o types.Object, name string) (token.Position, bool) {
+	s := types.NewMethodSet(types.NewPointer(o.Type()))
+	for sel := range s.Methods() {
+		if mo := sel.Obj(); mo.Name() == name {
+			return fs.Position(mo.Pos()), true
+		}
+	}
+	return token.Position{}, false
+}
+
 // NewSetConditions returns a NewMethod that writes a SetConditions method for
 // the supplied Object to the supplied file.
 func NewSetConditions(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameStatus, "SetConditions")
+		if err != nil {
+			return err
+		}
 		f.Commentf("SetConditions of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetConditions").Params(jen.Id("c").Op("...").Qual(runtime, "Condition")).Block(
-			jen.Id(receiver).Dot(fields.NameStatus).Dot("SetConditions").Call(jen.Id("c").Op("...")),
+			sel.Call(jen.Id("c").Op("...")),
 		)
+		return nil
 	}
 }
 
 // NewGetCondition returns a NewMethod that writes a GetCondition method for
 // the supplied Object to the supplied file.
 func NewGetCondition(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameStatus, "GetCondition")
+		if err != nil {
+			return err
+		}
 		f.Commentf("GetCondition of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetCondition").Params(jen.Id("ct").Qual(runtime, "ConditionType")).Qual(runtime, "Condition").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameStatus).Dot("GetCondition").Call(jen.Id("ct"))),
+			jen.Return(sel.Call(jen.Id("ct"))),
 		)
+		return nil
 	}
 }
 
 // NewSetResourceReference returns a NewMethod that writes a
 // SetResourceReference method for the supplied Object to the supplied file.
 func NewSetResourceReference(receiver, core string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "ResourceReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("SetResourceReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetResourceReference").Params(jen.Id("r").Op("*").Qual(core, "ObjectReference")).Block(
-			jen.Id(receiver).Dot(fields.NameSpec).Dot("ResourceReference").Op("=").Id("r"),
+			sel.Op("=").Id("r"),
 		)
+		return nil
 	}
 }
 
 // NewGetResourceReference returns a NewMethod that writes a
 // GetResourceReference method for the supplied Object to the supplied file.
 func NewGetResourceReference(receiver, core string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "ResourceReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("GetResourceReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetResourceReference").Params().Op("*").Qual(core, "ObjectReference").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameSpec).Dot("ResourceReference")),
+			jen.Return(sel),
 		)
+		return nil
 	}
 }
 
 // NewSetProviderConfigReference returns a NewMethod that writes a SetProviderConfigReference
 // method for the supplied Object to the supplied file.
 func NewSetProviderConfigReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "ProviderConfigReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("SetProviderConfigReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetProviderConfigReference").Params(jen.Id("r").Op("*").Qual(runtime, "Reference")).Block(
-			jen.Id(receiver).Dot(fields.NameSpec).Dot("ProviderConfigReference").Op("=").Id("r"),
+			sel.Op("=").Id("r"),
 		)
+		return nil
 	}
 }
 
 // NewGetProviderConfigReference returns a NewMethod that writes a GetProviderConfigReference
 // method for the supplied Object to the supplied file.
 func NewGetProviderConfigReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "ProviderConfigReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("GetProviderConfigReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetProviderConfigReference").Params().Op("*").Qual(runtime, "Reference").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameSpec).Dot("ProviderConfigReference")),
+			jen.Return(sel),
 		)
+		return nil
 	}
 }
 
 // NewSetTypedProviderConfigReference returns a NewMethod that writes a SetProviderConfigReference
 // method for the supplied Object to the supplied file.
 func NewSetTypedProviderConfigReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "ProviderConfigReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("SetProviderConfigReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetProviderConfigReference").Params(jen.Id("r").Op("*").Qual(runtime, "ProviderConfigReference")).Block(
-			jen.Id(receiver).Dot(fields.NameSpec).Dot("ProviderConfigReference").Op("=").Id("r"),
+			sel.Op("=").Id("r"),
 		)
+		return nil
 	}
 }
 
 // NewGetTypedProviderConfigReference returns a NewMethod that writes a GetProviderConfigReference
 // method for the supplied Object to the supplied file.
 func NewGetTypedProviderConfigReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "ProviderConfigReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("GetProviderConfigReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetProviderConfigReference").Params().Op("*").Qual(runtime, "ProviderConfigReference").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameSpec).Dot("ProviderConfigReference")),
+			jen.Return(sel),
 		)
+		return nil
 	}
 }
 
@@ -168,11 +251,16 @@ func NewGetTypedProviderConfigReference(receiver, runtime string) New {
 // SetWriteConnectionSecretToReference method for the supplied Object to the
 // supplied file.
 func NewSetWriteConnectionSecretToReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "WriteConnectionSecretToReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("SetWriteConnectionSecretToReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetWriteConnectionSecretToReference").Params(jen.Id("r").Op("*").Qual(runtime, "SecretReference")).Block(
-			jen.Id(receiver).Dot(fields.NameSpec).Dot("WriteConnectionSecretToReference").Op("=").Id("r"),
+			sel.Op("=").Id("r"),
 		)
+		return nil
 	}
 }
 
@@ -180,11 +268,16 @@ func NewSetWriteConnectionSecretToReference(receiver, runtime string) New {
 // GetWriteConnectionSecretToReference method for the supplied Object to the
 // supplied file.
 func NewGetWriteConnectionSecretToReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "WriteConnectionSecretToReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("GetWriteConnectionSecretToReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetWriteConnectionSecretToReference").Params().Op("*").Qual(runtime, "SecretReference").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameSpec).Dot("WriteConnectionSecretToReference")),
+			jen.Return(sel),
 		)
+		return nil
 	}
 }
 
@@ -192,11 +285,16 @@ func NewGetWriteConnectionSecretToReference(receiver, runtime string) New {
 // NewSetPublishConnectionDetailsTo method for the supplied Object to the
 // supplied file.
 func NewSetPublishConnectionDetailsTo(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "PublishConnectionDetailsTo")
+		if err != nil {
+			return err
+		}
 		f.Commentf("SetPublishConnectionDetailsTo of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetPublishConnectionDetailsTo").Params(jen.Id("r").Op("*").Qual(runtime, "PublishConnectionDetailsTo")).Block(
-			jen.Id(receiver).Dot(fields.NameSpec).Dot("PublishConnectionDetailsTo").Op("=").Id("r"),
+			sel.Op("=").Id("r"),
 		)
+		return nil
 	}
 }
 
@@ -204,11 +302,16 @@ func NewSetPublishConnectionDetailsTo(receiver, runtime string) New {
 // GetPublishConnectionDetailsTo method for the supplied Object to the
 // supplied file.
 func NewGetPublishConnectionDetailsTo(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "PublishConnectionDetailsTo")
+		if err != nil {
+			return err
+		}
 		f.Commentf("GetPublishConnectionDetailsTo of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetPublishConnectionDetailsTo").Params().Op("*").Qual(runtime, "PublishConnectionDetailsTo").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameSpec).Dot("PublishConnectionDetailsTo")),
+			jen.Return(sel),
 		)
+		return nil
 	}
 }
 
@@ -216,11 +319,16 @@ func NewGetPublishConnectionDetailsTo(receiver, runtime string) New {
 // SetWriteConnectionSecretToReference method for the supplied Object to the
 // supplied file.
 func NewLocalSetWriteConnectionSecretToReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "WriteConnectionSecretToReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("SetWriteConnectionSecretToReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetWriteConnectionSecretToReference").Params(jen.Id("r").Op("*").Qual(runtime, "LocalSecretReference")).Block(
-			jen.Id(receiver).Dot(fields.NameSpec).Dot("WriteConnectionSecretToReference").Op("=").Id("r"),
+			sel.Op("=").Id("r"),
 		)
+		return nil
 	}
 }
 
@@ -228,84 +336,148 @@ func NewLocalSetWriteConnectionSecretToReference(receiver, runtime string) New {
 // GetWriteConnectionSecretToReference method for the supplied Object to the
 // supplied file.
 func NewLocalGetWriteConnectionSecretToReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "WriteConnectionSecretToReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("GetWriteConnectionSecretToReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetWriteConnectionSecretToReference").Params().Op("*").Qual(runtime, "LocalSecretReference").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameSpec).Dot("WriteConnectionSecretToReference")),
+			jen.Return(sel),
 		)
+		return nil
 	}
 }
 
 // NewSetManagementPolicies returns a NewMethod that writes a SetManagementPolicies
 // method for the supplied Object to the supplied file.
 func NewSetManagementPolicies(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "ManagementPolicies")
+		if err != nil {
+			return err
+		}
 		f.Commentf("SetManagementPolicies of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetManagementPolicies").Params(jen.Id("r").Qual(runtime, "ManagementPolicies")).Block(
-			jen.Id(receiver).Dot(fields.NameSpec).Dot("ManagementPolicies").Op("=").Id("r"),
+			sel.Op("=").Id("r"),
 		)
+		return nil
 	}
 }
 
 // NewGetManagementPolicies returns a NewMethod that writes a GetManagementPolicies
 // method for the supplied Object to the supplied file.
 func NewGetManagementPolicies(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "ManagementPolicies")
+		if err != nil {
+			return err
+		}
 		f.Commentf("GetManagementPolicies of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetManagementPolicies").Params().Qual(runtime, "ManagementPolicies").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameSpec).Dot("ManagementPolicies")),
+			jen.Return(sel),
 		)
+		return nil
 	}
 }
 
 // NewSetDeletionPolicy returns a NewMethod that writes a SetDeletionPolicy
 // method for the supplied Object to the supplied file.
 func NewSetDeletionPolicy(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "DeletionPolicy")
+		if err != nil {
+			return err
+		}
 		f.Commentf("SetDeletionPolicy of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetDeletionPolicy").Params(jen.Id("r").Qual(runtime, "DeletionPolicy")).Block(
-			jen.Id(receiver).Dot(fields.NameSpec).Dot("DeletionPolicy").Op("=").Id("r"),
+			sel.Op("=").Id("r"),
 		)
+		return nil
 	}
 }
 
 // NewGetDeletionPolicy returns a NewMethod that writes a GetDeletionPolicy
 // method for the supplied Object to the supplied file.
 func NewGetDeletionPolicy(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "DeletionPolicy")
+		if err != nil {
+			return err
+		}
 		f.Commentf("GetDeletionPolicy of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetDeletionPolicy").Params().Qual(runtime, "DeletionPolicy").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameSpec).Dot("DeletionPolicy")),
+			jen.Return(sel),
 		)
+		return nil
 	}
 }
 
 // NewSetUsers returns a NewMethod that writes a SetUsers method for the
 // supplied Object to the supplied file.
 func NewSetUsers(receiver string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameStatus, "Users")
+		if err != nil {
+			return err
+		}
 		f.Commentf("SetUsers of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetUsers").Params(jen.Id("i").Int64()).Block(
-			jen.Id(receiver).Dot(fields.NameStatus).Dot("Users").Op("=").Id("i"),
+			sel.Op("=").Id("i"),
 		)
+		return nil
 	}
 }
 
 // NewGetUsers returns a NewMethod that writes a GetUsers method for the
 // supplied Object to the supplied file.
 func NewGetUsers(receiver string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameStatus, "Users")
+		if err != nil {
+			return err
+		}
 		f.Commentf("GetUsers of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetUsers").Params().Int64().Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameStatus).Dot("Users")),
+			jen.Return(sel),
 		)
+		return nil
+	}
+}
+
+// NewConvertProviderConfigReferences returns a New that writes functions that
+// convert typed ProviderConfig references between the supplied cluster scoped
+// ProviderConfig Object and its namespaced counterpart, to the supplied file.
+// The counterpart is the type of the same package whose name is the Object's
+// without its Cluster prefix, e.g. ProviderConfig for ClusterProviderConfig.
+func NewConvertProviderConfigReferences(runtime string) New {
+	return func(f *jen.File, o types.Object) error {
+		cluster := o.Name()
+		namespaced := strings.TrimPrefix(cluster, "Cluster")
+		if _, ok := o.Pkg().Scope().Lookup(namespaced).(*types.TypeName); !ok || namespaced == cluster {
+			return errors.Errorf("cannot find the namespaced counterpart of %s; its name must be %s without the Cluster prefix", cluster, cluster)
+		}
+		for _, kinds := range [][2]string{{namespaced, cluster}, {cluster, namespaced}} {
+			from, to := kinds[0], kinds[1]
+			f.Commentf("To%sReference converts the supplied reference to a %s to a reference to the %s of the same name. Other references are returned unchanged.", to, from, to)
+			f.Func().Id("To"+to+"Reference").Params(jen.Id("r").Op("*").Qual(runtime, "ProviderConfigReference")).Op("*").Qual(runtime, "ProviderConfigReference").Block(
+				jen.If(jen.Id("r").Op("==").Nil().Op("||").Id("r").Dot("Kind").Op("!=").Lit(from)).Block(
+					jen.Return(jen.Id("r")),
+				),
+				jen.Return(jen.Op("&").Qual(runtime, "ProviderConfigReference").Values(jen.Dict{
+					jen.Id("Name"): jen.Id("r").Dot("Name"),
+					jen.Id("Kind"): jen.Lit(to),
+				})),
+			)
+		}
+		return nil
 	}
 }
 
 // NewManagedGetItems returns a New that writes a GetItems method for the
 // supplied object to the supplied file.
 func NewManagedGetItems(receiver, resource string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
 		f.Commentf("GetItems of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetItems").Params().Index().Qual(resource, "Managed").Block(
 			jen.Id("items").Op(":=").Make(jen.Index().Qual(resource, "Managed"), jen.Len(jen.Id(receiver).Dot("Items"))),
@@ -314,6 +486,7 @@ func NewManagedGetItems(receiver, resource string) New {
 			),
 			jen.Return(jen.Id("items")),
 		)
+		return nil
 	}
 }
 
@@ -323,11 +496,16 @@ func NewManagedGetItems(receiver, resource string) New {
 // expects the ProviderConfigReference to be at the root of the struct, not
 // under its Spec field.
 func NewSetRootProviderConfigReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, "ProviderConfigReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("SetProviderConfigReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetProviderConfigReference").Params(jen.Id("r").Qual(runtime, "Reference")).Block(
-			jen.Id(receiver).Dot("ProviderConfigReference").Op("=").Id("r"),
+			sel.Op("=").Id("r"),
 		)
+		return nil
 	}
 }
 
@@ -337,40 +515,55 @@ func NewSetRootProviderConfigReference(receiver, runtime string) New {
 // method expects the ProviderConfigReference to be at the root of the struct,
 // not under its Spec field.
 func NewGetRootProviderConfigReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, "ProviderConfigReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("GetProviderConfigReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetProviderConfigReference").Params().Qual(runtime, "Reference").Block(
-			jen.Return(jen.Id(receiver).Dot("ProviderConfigReference")),
+			jen.Return(sel),
 		)
+		return nil
 	}
 }
 
 // NewSetRootResourceReference returns a NewMethod that writes a
 // SetRootResourceReference method for the supplied Object to the supplied file.
 func NewSetRootResourceReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, "ResourceReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("SetResourceReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetResourceReference").Params(jen.Id("r").Qual(runtime, "TypedReference")).Block(
-			jen.Id(receiver).Dot("ResourceReference").Op("=").Id("r"),
+			sel.Op("=").Id("r"),
 		)
+		return nil
 	}
 }
 
 // NewGetRootResourceReference returns a NewMethod that writes a
 // GetRootResourceReference method for the supplied Object to the supplied file.
 func NewGetRootResourceReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, "ResourceReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("GetResourceReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetResourceReference").Params().Qual(runtime, "TypedReference").Block(
-			jen.Return(jen.Id(receiver).Dot("ResourceReference")),
+			jen.Return(sel),
 		)
+		return nil
 	}
 }
 
 // NewProviderConfigUsageGetItems returns a New that writes a GetItems method for the
 // supplied object to the supplied file.
 func NewProviderConfigUsageGetItems(receiver, resource string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
 		f.Commentf("GetItems of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetItems").Params().Index().Qual(resource, "ProviderConfigUsage").Block(
 			jen.Id("items").Op(":=").Make(jen.Index().Qual(resource, "ProviderConfigUsage"), jen.Len(jen.Id(receiver).Dot("Items"))),
@@ -379,6 +572,7 @@ func NewProviderConfigUsageGetItems(receiver, resource string) New {
 			),
 			jen.Return(jen.Id("items")),
 		)
+		return nil
 	}
 }
 
@@ -388,11 +582,16 @@ func NewProviderConfigUsageGetItems(receiver, resource string) New {
 // expects the ProviderConfigReference to be at the root of the struct, not
 // under its Spec field.
 func NewSetRootProviderConfigTypedReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, "ProviderConfigReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("SetProviderConfigReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetProviderConfigReference").Params(jen.Id("r").Qual(runtime, "ProviderConfigReference")).Block(
-			jen.Id(receiver).Dot("ProviderConfigReference").Op("=").Id("r"),
+			sel.Op("=").Id("r"),
 		)
+		return nil
 	}
 }
 
@@ -402,10 +601,361 @@ func NewSetRootProviderConfigTypedReference(receiver, runtime string) New {
 // method expects the ProviderConfigReference to be at the root of the struct,
 // not under its Spec field.
 func NewGetRootProviderConfigTypedReference(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, "ProviderConfigReference")
+		if err != nil {
+			return err
+		}
 		f.Commentf("GetProviderConfigReference of this %s.", o.Name())
 		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetProviderConfigReference").Params().Qual(runtime, "ProviderConfigReference").Block(
-			jen.Return(jen.Id(receiver).Dot("ProviderConfigReference")),
+			jen.Return(sel),
+		)
+		return nil
+	}
+}
+
+// NewSetCompositionSelector returns a New that writes a SetCompositionSelector
+// method for the supplied object to the supplied file.
+func NewSetCompositionSelector(receiver, meta string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "CompositionSelector")
+		if err != nil {
+			return err
+		}
+		f.Commentf("SetCompositionSelector of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetCompositionSelector").Params(jen.Id("r").Op("*").Qual(meta, "LabelSelector")).Block(
+			sel.Op("=").Id("r"),
+		)
+		return nil
+	}
+}
+
+// NewGetCompositionSelector returns a New that writes a GetCompositionSelector
+// method for the supplied object to the supplied file.
+func NewGetCompositionSelector(receiver, meta string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "CompositionSelector")
+		if err != nil {
+			return err
+		}
+		f.Commentf("GetCompositionSelector of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetCompositionSelector").Params().Op("*").Qual(meta, "LabelSelector").Block(
+			jen.Return(sel),
+		)
+		return nil
+	}
+}
+
+// NewSetCompositionReference returns a New that writes a
+// SetCompositionReference method for the supplied object to the supplied file.
+func NewSetCompositionReference(receiver, core string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "CompositionReference")
+		if err != nil {
+			return err
+		}
+		f.Commentf("SetCompositionReference of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetCompositionReference").Params(jen.Id("r").Op("*").Qual(core, "ObjectReference")).Block(
+			sel.Op("=").Id("r"),
+		)
+		return nil
+	}
+}
+
+// NewGetCompositionReference returns a New that writes a
+// GetCompositionReference method for the supplied object to the supplied file.
+func NewGetCompositionReference(receiver, core string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "CompositionReference")
+		if err != nil {
+			return err
+		}
+		f.Commentf("GetCompositionReference of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetCompositionReference").Params().Op("*").Qual(core, "ObjectReference").Block(
+			jen.Return(sel),
+		)
+		return nil
+	}
+}
+
+// NewSetCompositionRevisionReference returns a New that writes a
+// SetCompositionRevisionReference method for the supplied object to the
+// supplied file.
+func NewSetCompositionRevisionReference(receiver, core string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "CompositionRevisionReference")
+		if err != nil {
+			return err
+		}
+		f.Commentf("SetCompositionRevisionReference of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetCompositionRevisionReference").Params(jen.Id("r").Op("*").Qual(core, "LocalObjectReference")).Block(
+			sel.Op("=").Id("r"),
+		)
+		return nil
+	}
+}
+
+// NewGetCompositionRevisionReference returns a New that writes a
+// GetCompositionRevisionReference method for the supplied object to the
+// supplied file.
+func NewGetCompositionRevisionReference(receiver, core string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "CompositionRevisionReference")
+		if err != nil {
+			return err
+		}
+		f.Commentf("GetCompositionRevisionReference of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetCompositionRevisionReference").Params().Op("*").Qual(core, "LocalObjectReference").Block(
+			jen.Return(sel),
+		)
+		return nil
+	}
+}
+
+// NewSetCompositionUpdatePolicy returns a New that writes a
+// SetCompositionUpdatePolicy method for the supplied object to the supplied
+// file.
+func NewSetCompositionUpdatePolicy(receiver, runtime string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "CompositionUpdatePolicy")
+		if err != nil {
+			return err
+		}
+		f.Commentf("SetCompositionUpdatePolicy of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetCompositionUpdatePolicy").Params(jen.Id("r").Op("*").Qual(runtime, "UpdatePolicy")).Block(
+			sel.Op("=").Id("r"),
+		)
+		return nil
+	}
+}
+
+// NewGetCompositionUpdatePolicy returns a New that writes a
+// GetCompositionUpdatePolicy method for the supplied object to the supplied
+// file.
+func NewGetCompositionUpdatePolicy(receiver, runtime string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "CompositionUpdatePolicy")
+		if err != nil {
+			return err
+		}
+		f.Commentf("GetCompositionUpdatePolicy of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetCompositionUpdatePolicy").Params().Op("*").Qual(runtime, "UpdatePolicy").Block(
+			jen.Return(sel),
+		)
+		return nil
+	}
+}
+
+// NewSetCompositeDeletePolicy returns a New that writes a
+// SetCompositeDeletePolicy method for the supplied object to the supplied file.
+func NewSetCompositeDeletePolicy(receiver, runtime string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "CompositeDeletePolicy")
+		if err != nil {
+			return err
+		}
+		f.Commentf("SetCompositeDeletePolicy of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetCompositeDeletePolicy").Params(jen.Id("r").Op("*").Qual(runtime, "CompositeDeletePolicy")).Block(
+			sel.Op("=").Id("r"),
+		)
+		return nil
+	}
+}
+
+// NewGetCompositeDeletePolicy returns a New that writes a
+// GetCompositeDeletePolicy method for the supplied object to the supplied file.
+func NewGetCompositeDeletePolicy(receiver, runtime string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "CompositeDeletePolicy")
+		if err != nil {
+			return err
+		}
+		f.Commentf("GetCompositeDeletePolicy of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetCompositeDeletePolicy").Params().Op("*").Qual(runtime, "CompositeDeletePolicy").Block(
+			jen.Return(sel),
+		)
+		return nil
+	}
+}
+
+// NewSetResourceReferences returns a New that writes a SetResourceReferences
+// method for the supplied object to the supplied file.
+func NewSetResourceReferences(receiver, core string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "ResourceReferences")
+		if err != nil {
+			return err
+		}
+		f.Commentf("SetResourceReferences of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetResourceReferences").Params(jen.Id("r").Index().Qual(core, "ObjectReference")).Block(
+			sel.Op("=").Id("r"),
+		)
+		return nil
+	}
+}
+
+// NewGetResourceReferences returns a New that writes a GetResourceReferences
+// method for the supplied object to the supplied file.
+func NewGetResourceReferences(receiver, core string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "ResourceReferences")
+		if err != nil {
+			return err
+		}
+		f.Commentf("GetResourceReferences of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetResourceReferences").Params().Index().Qual(core, "ObjectReference").Block(
+			jen.Return(sel),
+		)
+		return nil
+	}
+}
+
+// NewSetClaimReference returns a New that writes a SetClaimReference method for
+// the supplied object to the supplied file.
+func NewSetClaimReference(receiver, reference string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "ClaimReference")
+		if err != nil {
+			return err
+		}
+		f.Commentf("SetClaimReference of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetClaimReference").Params(jen.Id("r").Op("*").Qual(reference, "Claim")).Block(
+			sel.Op("=").Id("r"),
+		)
+		return nil
+	}
+}
+
+// NewGetClaimReference returns a New that writes a GetClaimReference method for
+// the supplied object to the supplied file.
+func NewGetClaimReference(receiver, reference string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameSpec, "ClaimReference")
+		if err != nil {
+			return err
+		}
+		f.Commentf("GetClaimReference of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetClaimReference").Params().Op("*").Qual(reference, "Claim").Block(
+			jen.Return(sel),
+		)
+		return nil
+	}
+}
+
+// NewSetConnectionDetailsLastPublishedTime returns a New that writes a
+// SetConnectionDetailsLastPublishedTime method for the supplied object to the
+// supplied file.
+func NewSetConnectionDetailsLastPublishedTime(receiver, meta string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameStatus, "ConnectionDetails", "LastPublishedTime")
+		if err != nil {
+			return err
+		}
+		f.Commentf("SetConnectionDetailsLastPublishedTime of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetConnectionDetailsLastPublishedTime").Params(jen.Id("r").Op("*").Qual(meta, "Time")).Block(
+			sel.Op("=").Id("r"),
+		)
+		return nil
+	}
+}
+
+// NewGetConnectionDetailsLastPublishedTime returns a New that writes a
+// GetConnectionDetailsLastPublishedTime method for the supplied object to the
+// supplied file.
+func NewGetConnectionDetailsLastPublishedTime(receiver, meta string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, fields.NameStatus, "ConnectionDetails", "LastPublishedTime")
+		if err != nil {
+			return err
+		}
+		f.Commentf("GetConnectionDetailsLastPublishedTime of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetConnectionDetailsLastPublishedTime").Params().Op("*").Qual(meta, "Time").Block(
+			jen.Return(sel),
+		)
+		return nil
+	}
+}
+
+// NewFieldSetter returns a New that writes a Set<name> method for the supplied
+// object to the supplied file. The method sets the field at the supplied path,
+// which is of the supplied type.
+func NewFieldSetter(receiver, name string, typ jen.Code, path ...string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, path...)
+		if err != nil {
+			return err
+		}
+		f.Commentf("Set%s of this %s.", name, o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("Set" + name).Params(jen.Id("r").Add(typ)).Block(
+			sel.Op("=").Id("r"),
+		)
+		return nil
+	}
+}
+
+// NewFieldGetter returns a New that writes a Get<name> method for the supplied
+// object to the supplied file. The method returns the field at the supplied
+// path, which is of the supplied type.
+func NewFieldGetter(receiver, name string, typ jen.Code, path ...string) New {
+	return func(f *jen.File, o types.Object) error {
+		sel, err := selector(o, receiver, path...)
+		if err != nil {
+			return err
+		}
+		f.Commentf("Get%s of this %s.", name, o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("Get" + name).Params().Add(typ).Block(
+			jen.Return(sel),
+		)
+		return nil
+	}
+}
+
+// NewSetDependencyStatus returns a New that writes a SetDependencyStatus
+// method for the supplied object to the supplied file.
+func NewSetDependencyStatus(receiver string) New {
+	return func(f *jen.File, o types.Object) error {
+		found, err := selector(o, receiver, fields.NameStatus, "FoundDependencies")
+		if err != nil {
+			return err
+		}
+		installed, err := selector(o, receiver, fields.NameStatus, "InstalledDependencies")
+		if err != nil {
+			return err
+		}
+		invalid, err := selector(o, receiver, fields.NameStatus, "InvalidDependencies")
+		if err != nil {
+			return err
+		}
+		f.Commentf("SetDependencyStatus of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetDependencyStatus").Params(jen.List(jen.Id("found"), jen.Id("installed"), jen.Id("invalid")).Int64()).Block(
+			found.Op("=").Id("found"),
+			installed.Op("=").Id("installed"),
+			invalid.Op("=").Id("invalid"),
+		)
+		return nil
+	}
+}
+
+// NewGetDependencyStatus returns a New that writes a GetDependencyStatus
+// method for the supplied object to the supplied file.
+func NewGetDependencyStatus(receiver string) New {
+	return func(f *jen.File, o types.Object) error {
+		found, err := selector(o, receiver, fields.NameStatus, "FoundDependencies")
+		if err != nil {
+			return err
+		}
+		installed, err := selector(o, receiver, fields.NameStatus, "InstalledDependencies")
+		if err != nil {
+			return err
+		}
+		invalid, err := selector(o, receiver, fields.NameStatus, "InvalidDependencies")
+		if err != nil {
+			return err
+		}
+		f.Commentf("GetDependencyStatus of this %s.", o.Name())
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetDependencyStatus").Params().Params(jen.List(jen.Id("found"), jen.Id("installed"), jen.Id("invalid")).Int64()).Block(
+			jen.Return(found, installed, invalid),
 		)
+		return nil
 	}
 }
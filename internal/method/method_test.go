@@ -18,6 +18,9 @@ package method
 
 import (
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"go/types"
 	"testing"
 
@@ -35,6 +38,66 @@ func (o MockObject) Name() string {
 	return o.Named
 }
 
+// Type returns a type with the fields and methods generated methods use.
+func (o MockObject) Type() types.Type {
+	return mockType
+}
+
+var mockType = func() types.Type {
+	src := `package pkg
+
+type Spec struct {
+	ResourceReference                int
+	ProviderConfigReference          int
+	WriteConnectionSecretToReference int
+	PublishConnectionDetailsTo       int
+	ManagementPolicies               int
+	DeletionPolicy                   int
+	CompositionReference             int
+	ResourceReferences               int
+}
+
+type ConnectionDetails struct {
+	LastPublishedTime int
+}
+
+type Status struct {
+	Users             int
+	ConnectionDetails ConnectionDetails
+
+	FoundDependencies     int64
+	InstalledDependencies int64
+	InvalidDependencies   int64
+}
+
+func (s *Status) SetConditions() {}
+func (s *Status) GetCondition()  {}
+
+type Type struct {
+	Spec   Spec
+	Status Status
+
+	ProviderConfigReference int
+	ResourceReference       int
+}
+`
+	return checkType(src, "Type")
+}()
+
+// checkType type checks the supplied source and returns the named type.
+func checkType(src, name string) types.Type {
+	fs := token.NewFileSet()
+	f, err := parser.ParseFile(fs, "pkg.go", src, 0)
+	if err != nil {
+		panic(err)
+	}
+	p, err := (&types.Config{}).Check("pkg", fs, []*ast.File{f}, nil)
+	if err != nil {
+		panic(err)
+	}
+	return p.Scope().Lookup(name).Type()
+}
+
 func TestNewSetConditions(t *testing.T) {
 	want := `package pkg
 
@@ -337,6 +400,56 @@ func (t *Type) GetUsers() int64 {
 	}
 }
 
+func TestNewConvertProviderConfigReferences(t *testing.T) {
+	want := `package pkg
+
+import runtime "example.org/runtime"
+
+// ToClusterTypeReference converts the supplied reference to a Type to a reference to the ClusterType of the same name. Other references are returned unchanged.
+func ToClusterTypeReference(r *runtime.ProviderConfigReference) *runtime.ProviderConfigReference {
+	if r == nil || r.Kind != "Type" {
+		return r
+	}
+	return &runtime.ProviderConfigReference{
+		Kind: "ClusterType",
+		Name: r.Name,
+	}
+}
+
+// ToTypeReference converts the supplied reference to a ClusterType to a reference to the Type of the same name. Other references are returned unchanged.
+func ToTypeReference(r *runtime.ProviderConfigReference) *runtime.ProviderConfigReference {
+	if r == nil || r.Kind != "ClusterType" {
+		return r
+	}
+	return &runtime.ProviderConfigReference{
+		Kind: "Type",
+		Name: r.Name,
+	}
+}
+`
+	src := `package pkg
+
+type Type struct{}
+
+type ClusterType struct{}
+
+type ClusterOrphan struct{}
+`
+	f := jen.NewFilePath("pkg")
+	o := checkType(src, "ClusterType").(*types.Named).Obj()
+	if err := NewConvertProviderConfigReferences("example.org/runtime")(f, o); err != nil {
+		t.Fatalf("NewConvertProviderConfigReferences(): %v", err)
+	}
+	if diff := cmp.Diff(want, fmt.Sprintf("%#v", f)); diff != "" {
+		t.Errorf("NewConvertProviderConfigReferences(): -want, +got\n%s", diff)
+	}
+
+	orphan := checkType(src, "ClusterOrphan").(*types.Named).Obj()
+	if err := NewConvertProviderConfigReferences("example.org/runtime")(jen.NewFilePath("pkg"), orphan); err == nil {
+		t.Errorf("NewConvertProviderConfigReferences(): want error for a type without a namespaced counterpart")
+	}
+}
+
 func TestNewManagedGetItems(t *testing.T) {
 	want := `package pkg
 
@@ -514,3 +627,176 @@ func (t *Type) GetProviderConfigReference() runtime.ProviderConfigReference {
 		t.Errorf("NewGetRootProviderConfigReference(): -want, +got\n%s", diff)
 	}
 }
+
+func TestNewSetCompositionReference(t *testing.T) {
+	want := `package pkg
+
+import core "example.org/core"
+
+// SetCompositionReference of this Type.
+func (t *Type) SetCompositionReference(r *core.ObjectReference) {
+	t.Spec.CompositionReference = r
+}
+`
+	f := jen.NewFilePath("pkg")
+	NewSetCompositionReference("t", "example.org/core")(f, MockObject{Named: "Type"})
+	if diff := cmp.Diff(want, fmt.Sprintf("%#v", f)); diff != "" {
+		t.Errorf("NewSetCompositionReference(): -want, +got\n%s", diff)
+	}
+}
+
+func TestNewGetResourceReferences(t *testing.T) {
+	want := `package pkg
+
+import core "example.org/core"
+
+// GetResourceReferences of this Type.
+func (t *Type) GetResourceReferences() []core.ObjectReference {
+	return t.Spec.ResourceReferences
+}
+`
+	f := jen.NewFilePath("pkg")
+	NewGetResourceReferences("t", "example.org/core")(f, MockObject{Named: "Type"})
+	if diff := cmp.Diff(want, fmt.Sprintf("%#v", f)); diff != "" {
+		t.Errorf("NewGetResourceReferences(): -want, +got\n%s", diff)
+	}
+}
+
+func TestNewSetConnectionDetailsLastPublishedTime(t *testing.T) {
+	want := `package pkg
+
+import meta "example.org/meta"
+
+// SetConnectionDetailsLastPublishedTime of this Type.
+func (t *Type) SetConnectionDetailsLastPublishedTime(r *meta.Time) {
+	t.Status.ConnectionDetails.LastPublishedTime = r
+}
+`
+	f := jen.NewFilePath("pkg")
+	NewSetConnectionDetailsLastPublishedTime("t", "example.org/meta")(f, MockObject{Named: "Type"})
+	if diff := cmp.Diff(want, fmt.Sprintf("%#v", f)); diff != "" {
+		t.Errorf("NewSetConnectionDetailsLastPublishedTime(): -want, +got\n%s", diff)
+	}
+}
+
+func TestNewFieldSetter(t *testing.T) {
+	want := `package pkg
+
+import core "example.org/core"
+
+// SetPackagePullSecrets of this Type.
+func (t *Type) SetPackagePullSecrets(r []core.LocalObjectReference) {
+	t.Spec.ResourceReference = r
+}
+`
+	f := jen.NewFilePath("pkg")
+	NewFieldSetter("t", "PackagePullSecrets", jen.Index().Qual("example.org/core", "LocalObjectReference"), "Spec", "ResourceReference")(f, MockObject{Named: "Type"})
+	if diff := cmp.Diff(want, fmt.Sprintf("%#v", f)); diff != "" {
+		t.Errorf("NewFieldSetter(): -want, +got\n%s", diff)
+	}
+}
+
+func TestNewFieldGetter(t *testing.T) {
+	want := `package pkg
+
+// GetRevision of this Type.
+func (t *Type) GetRevision() *int64 {
+	return t.Spec.DeletionPolicy
+}
+`
+	f := jen.NewFilePath("pkg")
+	NewFieldGetter("t", "Revision", jen.Op("*").Int64(), "Spec", "DeletionPolicy")(f, MockObject{Named: "Type"})
+	if diff := cmp.Diff(want, fmt.Sprintf("%#v", f)); diff != "" {
+		t.Errorf("NewFieldGetter(): -want, +got\n%s", diff)
+	}
+}
+
+func TestNewSetDependencyStatus(t *testing.T) {
+	want := `package pkg
+
+// SetDependencyStatus of this Type.
+func (t *Type) SetDependencyStatus(found, installed, invalid int64) {
+	t.Status.FoundDependencies = found
+	t.Status.InstalledDependencies = installed
+	t.Status.InvalidDependencies = invalid
+}
+`
+	f := jen.NewFilePath("pkg")
+	NewSetDependencyStatus("t")(f, MockObject{Named: "Type"})
+	if diff := cmp.Diff(want, fmt.Sprintf("%#v", f)); diff != "" {
+		t.Errorf("NewSetDependencyStatus(): -want, +got\n%s", diff)
+	}
+}
+
+func TestSelector(t *testing.T) {
+	typ := checkType(`package pkg
+
+type ResourceSpec struct {
+	DeletionPolicy int
+}
+
+type CommonSpec struct {
+	ResourceSpec
+}
+
+type OtherSpec struct {
+	DeletionPolicy int
+}
+
+type Spec struct {
+	CommonSpec
+}
+
+type AmbiguousSpec struct {
+	CommonSpec
+	OtherCommon
+}
+
+type OtherCommon struct {
+	OtherSpec
+}
+
+type Type struct {
+	Spec      Spec
+	Ambiguous AmbiguousSpec
+}
+`, "Type")
+	o := types.NewTypeName(0, nil, "Type", typ)
+
+	type want struct {
+		sel string
+		err string
+	}
+	cases := map[string]struct {
+		path []string
+		want want
+	}{
+		"Promoted": {
+			path: []string{"Spec", "DeletionPolicy"},
+			want: want{sel: "t.Spec.DeletionPolicy"},
+		},
+		"Ambiguous": {
+			path: []string{"Ambiguous", "DeletionPolicy"},
+			want: want{err: "Type.Ambiguous.DeletionPolicy is ambiguous; it is promoted from more than one embedded struct"},
+		},
+		"Missing": {
+			path: []string{"Spec", "Nope"},
+			want: want{err: "Type.Spec has no field or method Nope"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := selector(o, "t", tc.path...)
+			got := want{}
+			if err != nil {
+				got.err = err.Error()
+			}
+			if s != nil {
+				got.sel = fmt.Sprintf("%#v", s)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("selector(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
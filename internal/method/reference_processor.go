@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/crossplane/crossplane-tools/internal/comments"
+	xptypes "github.com/crossplane/crossplane-tools/internal/types"
 )
 
 // Comment markers used by ReferenceProcessor.
@@ -37,6 +38,18 @@ const (
 
 var regexFunctionCall = regexp.MustCompile(`((.+)\.)?([^.]+\(.*\))`)
 
+// A ValueType is the type of the value a reference resolves to, or of the
+// elements of a slice of values.
+type ValueType string
+
+// Value types of reference fields.
+const (
+	ValueTypeString ValueType = "string"
+	ValueTypeInt    ValueType = "int64"
+	ValueTypeFloat  ValueType = "float64"
+	ValueTypeBool   ValueType = "bool"
+)
+
 // Reference is the internal representation that has enough information to let
 // us generate the resolver.
 type Reference struct {
@@ -55,19 +68,22 @@ type Reference struct {
 
 	// GoValueFieldPath is the list of fields that needs to be traveled to access
 	// the current value field. It may include prefixes like [] for array fields,
-	// * for pointer fields or []* for array of pointer fields.
+	// * for pointer fields, []* for array of pointer fields, or map[] and map[]*
+	// for map fields.
 	GoValueFieldPath []string
 
 	// GoRefFieldName is the name of the field that holds the reference (or slice
 	// of references): *xpv1.Reference / []xpv1.Reference for legacy resources, or
 	// *xpv2.Reference / *xpv2.NamespacedReference (and their slice forms) for
-	// cluster-scoped and namespaced v2 resources respectively.
+	// cluster-scoped and namespaced v2 resources respectively. Map fields hold
+	// a map with the same key type, for example map[string]*xpv1.Reference or
+	// map[string][]xpv1.Reference.
 	GoRefFieldName string
 
 	// GoSelectorFieldName is the name of the field that holds the selector:
 	// *xpv1.Selector for legacy resources, or *xpv2.Selector /
 	// *xpv2.NamespacedSelector for cluster-scoped and namespaced v2 resources
-	// respectively.
+	// respectively. Map fields don't use a selector.
 	GoSelectorFieldName string
 
 	// IsSlice tells whether the current value type is a slice kind.
@@ -76,8 +92,22 @@ type Reference struct {
 	// IsPointer tells whether the current value type is a pointer kind.
 	IsPointer bool
 
-	// IsFloatPointer tells whether the current value pointer is of type float64
-	IsFloatPointer bool
+	// IsMap tells whether the current value type is a map kind. The value of
+	// each key of the map of references is resolved separately, and stored
+	// under the same key. IsSlice and IsPointer describe the map's values.
+	IsMap bool
+
+	// MapType is the type of the current value, if it is a map.
+	MapType *jen.Statement
+
+	// ValueType is the type of the current value, or of its elements if it
+	// is a slice, once any pointer is dereferenced.
+	ValueType ValueType
+
+	// Helper is set if the current value is of a recursive or nested type,
+	// rather than a reference. Its references are resolved by a helper
+	// function. Only GoValueFieldPath is set for such values.
+	Helper *types.Named
 }
 
 // ReferenceProcessorOption is used to configure ReferenceProcessor.
@@ -91,10 +121,19 @@ func WithDefaultExtractor(ext *jen.Statement) ReferenceProcessorOption {
 	}
 }
 
+// WithRoot returns an option that sets the variable field paths start from.
+// They start from the receiver by default.
+func WithRoot(root string) ReferenceProcessorOption {
+	return func(rp *ReferenceProcessor) {
+		rp.Root = root
+	}
+}
+
 // NewReferenceProcessor returns a new *ReferenceProcessor .
 func NewReferenceProcessor(receiver string, opts ...ReferenceProcessorOption) *ReferenceProcessor {
 	rp := &ReferenceProcessor{
 		Receiver: receiver,
+		Root:     receiver,
 	}
 	for _, f := range opts {
 		f(rp)
@@ -108,9 +147,13 @@ type ReferenceProcessor struct {
 	// DefaultExtractor is used when the extractor is not overridden.
 	DefaultExtractor *jen.Statement
 
-	// Receiver is prepended to all field paths.
+	// Receiver of the generated method, whose namespace references are
+	// resolved in.
 	Receiver string
 
+	// Root is prepended to all field paths.
+	Root string
+
 	refs []Reference
 }
 
@@ -122,28 +165,17 @@ func (rp *ReferenceProcessor) Process(_ *types.Named, f *types.Var, _, comment s
 		return nil
 	}
 	refType := refTypeValues[0]
-	isPointer := false
-	isList := false
-	isFloatPointer := false
+	shape, err := valueShapeOf(f.Type())
+	if err != nil {
+		return err
+	}
 	refFieldName := f.Name() + "Ref"
-
-	// We don't support *[]string.
-	switch t := f.Type().(type) {
-	// *string|*float64
-	case *types.Pointer:
-		isPointer = true
-	// []string|[]float64.
-	case *types.Slice:
-		isList = true
+	if shape.IsSlice || shape.IsMap {
 		refFieldName = f.Name() + "Refs"
-		// []*string|[]*float64
-		if _, ok := t.Elem().(*types.Pointer); ok {
-			isPointer = true
-		}
 	}
-
-	if strings.HasSuffix(f.Type().String(), "*float64") {
-		isFloatPointer = true
+	var mapType *jen.Statement
+	if shape.IsMap {
+		mapType = typeCode(f.Type())
 	}
 
 	extractorPath := rp.DefaultExtractor
@@ -163,7 +195,7 @@ func (rp *ReferenceProcessor) Process(_ *types.Named, f *types.Var, _, comment s
 	if values, ok := markers[ReferenceSelectorFieldNameMarker]; ok {
 		selectorFieldName = values[0]
 	}
-	path := append([]string{rp.Receiver}, parentFields...)
+	path := append([]string{rp.Root}, parentFields...)
 	rp.refs = append(rp.refs, Reference{
 		RemoteType:          getTypeCodeFromPath(refType),
 		RemoteListType:      getTypeCodeFromPath(refType, "List"),
@@ -172,9 +204,99 @@ func (rp *ReferenceProcessor) Process(_ *types.Named, f *types.Var, _, comment s
 		GoRefFieldName:      refFieldName,
 		GoSelectorFieldName: selectorFieldName,
 		GetNamespace:        jen.Id(rp.Receiver).Dot("GetNamespace").Call(),
-		IsPointer:           isPointer,
-		IsSlice:             isList,
-		IsFloatPointer:      isFloatPointer,
+		IsPointer:           shape.IsPointer,
+		IsSlice:             shape.IsSlice,
+		IsMap:               shape.IsMap,
+		MapType:             mapType,
+		ValueType:           shape.Type,
+	})
+	return nil
+}
+
+// A valueShape describes the type of the value of a reference field.
+type valueShape struct {
+	Type      ValueType
+	IsMap     bool
+	IsSlice   bool
+	IsPointer bool
+}
+
+// valueShapeOf returns the shape of the value of a reference field. It returns
+// an error if generated resolvers can't convert the field to and from a string.
+// Supported are string, int64, float64 and bool values, pointers to string,
+// int64 and float64 values, slices of strings and of those pointers, and maps
+// with ordered keys whose values are any of these. Named types, such as a type
+// SubnetID string, are not supported.
+func valueShapeOf(t types.Type) (valueShape, error) {
+	s := valueShape{}
+	et := types.Unalias(t)
+	if m, ok := et.(*types.Map); ok {
+		if !xptypes.IsOrdered(m.Key()) {
+			return valueShape{}, errors.Errorf("unsupported type %s: map reference fields must have ordered keys", t)
+		}
+		s.IsMap = true
+		et = types.Unalias(m.Elem())
+	}
+	if sl, ok := et.(*types.Slice); ok {
+		s.IsSlice = true
+		et = types.Unalias(sl.Elem())
+	}
+	if p, ok := et.(*types.Pointer); ok {
+		s.IsPointer = true
+		et = types.Unalias(p.Elem())
+	}
+	if n, ok := et.(*types.Named); ok {
+		if _, ok := n.Underlying().(*types.Basic); ok {
+			return valueShape{}, errors.Errorf("unsupported type %s: reference fields of named types are not supported, use %s instead", t, n.Underlying())
+		}
+	}
+	if b, ok := et.(*types.Basic); ok {
+		s.Type = map[types.BasicKind]ValueType{
+			types.String:  ValueTypeString,
+			types.Int64:   ValueTypeInt,
+			types.Float64: ValueTypeFloat,
+			types.Bool:    ValueTypeBool,
+		}[b.Kind()]
+	}
+
+	switch {
+	case s.Type == "":
+	case s.Type == ValueTypeString:
+		return s, nil
+	case s.IsPointer && s.Type != ValueTypeBool:
+		return s, nil
+	case !s.IsPointer && !s.IsSlice:
+		return s, nil
+	}
+	return valueShape{}, errors.Errorf("unsupported type %s: reference fields must be string, int64, float64 or bool, *string, *int64 or *float64, a slice of strings or of those pointers, or a map of any of these", t)
+}
+
+// typeCode returns code that refers to the supplied type.
+func typeCode(t types.Type) *jen.Statement {
+	switch t := types.Unalias(t).(type) {
+	case *types.Named:
+		if t.Obj().Pkg() == nil {
+			return jen.Id(t.Obj().Name())
+		}
+		return jen.Qual(t.Obj().Pkg().Path(), t.Obj().Name())
+	case *types.Pointer:
+		return jen.Op("*").Add(typeCode(t.Elem()))
+	case *types.Slice:
+		return jen.Index().Add(typeCode(t.Elem()))
+	case *types.Map:
+		return jen.Map(typeCode(t.Key())).Add(typeCode(t.Elem()))
+	}
+	return jen.Id(t.String())
+}
+
+// ProcessHelper stores the supplied field of a recursive or nested type, so
+// that a helper function that resolves the references of the type can be
+// called for it. It satisfies types.CycleProcessorFn and
+// types.NestedProcessorFn.
+func (rp *ReferenceProcessor) ProcessHelper(n *types.Named, parentFields ...string) error {
+	rp.refs = append(rp.refs, Reference{
+		GoValueFieldPath: append([]string{rp.Root}, parentFields...),
+		Helper:           n,
 	})
 	return nil
 }
@@ -194,6 +316,13 @@ func getTypeCodeFromPath(path string, nameSuffix ...string) *jen.Statement {
 	return jen.Op("&").Qual(pkg, name).Values()
 }
 
+// ValidateExtractor returns an error if the supplied value of an extractor
+// marker is not a function call that ReferenceProcessor can use.
+func ValidateExtractor(path string) error {
+	_, err := getFuncCodeFromPath(path)
+	return err
+}
+
 func getFuncCodeFromPath(path string) (*jen.Statement, error) {
 	parts := regexFunctionCall.FindStringSubmatch(path)
 	// we have a total of four groups in the regular expression so if
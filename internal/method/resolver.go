@@ -19,6 +19,7 @@ package method
 import (
 	"fmt"
 	"go/types"
+	"slices"
 	"strings"
 
 	"github.com/dave/jennifer/jen"
@@ -29,6 +30,7 @@ import (
 
 const (
 	funcnameNewAPINamespacedResolver          = "NewAPINamespacedResolver"
+	typenameAPINamespacedResolver             = "APINamespacedResolver"
 	typenameNamespacedResolutionRequest       = "NamespacedResolutionRequest"
 	typenameNamespacedResolutionResponse      = "NamespacedResolutionResponse"
 	typenameMultiNamespacedResolutionRequest  = "MultiNamespacedResolutionRequest"
@@ -37,6 +39,7 @@ const (
 
 const (
 	funcnameNewAPIResolver          = "NewAPIResolver"
+	typenameAPIResolver             = "APIResolver"
 	typenameResolutionRequest       = "ResolutionRequest"
 	typenameResolutionResponse      = "ResolutionResponse"
 	typenameMultiResolutionRequest  = "MultiResolutionRequest"
@@ -45,220 +48,592 @@ const (
 
 type names struct {
 	APIResolverFunctionName         string
+	APIResolverTypeName             string
 	ResolutionRequestTypeName       string
 	ResolutionResponseTypeName      string
 	MultiResolutionRequestTypeName  string
 	MultiResolutionResponseTypeName string
 }
 
+// A ResolveReferencesOption configures the ResolveReferences method of a
+// managed resource.
+type ResolveReferencesOption func(o *resolveReferencesOptions)
+
+type resolveReferencesOptions struct {
+	typeHelpers   bool
+	roots         []string
+	rejectOutside bool
+}
+
+// WithTypeHelpers returns an option that resolves the references of each
+// nested named struct type in a helper method, rather than inline in the
+// ResolveReferences method. The helper methods are named after the type, e.g.
+// resolveSubnetSpecReferences, and call the helper methods of the types
+// nested in their type.
+func WithTypeHelpers() ResolveReferencesOption {
+	return func(o *resolveReferencesOptions) {
+		o.typeHelpers = true
+	}
+}
+
+// WithRoots returns an option that only resolves the references of the fields
+// at the supplied paths, for example Spec.ForProvider and Spec.InitProvider.
+// References elsewhere, for example in Status, are ignored. Writing the method
+// fails if the managed resource doesn't have a field at each path.
+func WithRoots(paths ...string) ResolveReferencesOption {
+	return func(o *resolveReferencesOptions) {
+		o.roots = paths
+	}
+}
+
+// WithRejectOutsideRoots returns an option that fails to write the
+// ResolveReferences method of a managed resource that has references outside
+// the roots configured by WithRoots, rather than ignoring them.
+func WithRejectOutsideRoots() ResolveReferencesOption {
+	return func(o *resolveReferencesOptions) {
+		o.rejectOutside = true
+	}
+}
+
 // NewResolveReferences returns a NewMethod that writes a ResolveReferences for
 // given managed resource, if needed.
-func NewResolveReferences(traverser *xptypes.Traverser, receiver, clientPath, referencePkgPath string) New {
+func NewResolveReferences(traverser *xptypes.Traverser, receiver, clientPath, referencePkgPath string, o ...ResolveReferencesOption) New {
 	return NewResolveReferencesCommon(traverser, receiver, clientPath, referencePkgPath, names{
 		APIResolverFunctionName:         funcnameNewAPIResolver,
+		APIResolverTypeName:             typenameAPIResolver,
 		ResolutionRequestTypeName:       typenameResolutionRequest,
 		ResolutionResponseTypeName:      typenameResolutionResponse,
 		MultiResolutionRequestTypeName:  typenameMultiResolutionRequest,
 		MultiResolutionResponseTypeName: typenameMultiResolutionResponse,
-	})
+	}, o...)
 }
 
 // NewResolveReferencesV2 returns a NewMethod that writes a ResolveReferences for
 // given managed resource, if needed.
-func NewResolveReferencesV2(traverser *xptypes.Traverser, receiver, clientPath, referencePkgPath string) New {
+func NewResolveReferencesV2(traverser *xptypes.Traverser, receiver, clientPath, referencePkgPath string, o ...ResolveReferencesOption) New {
 	return NewResolveReferencesCommon(traverser, receiver, clientPath, referencePkgPath, names{
 		APIResolverFunctionName:         funcnameNewAPINamespacedResolver,
+		APIResolverTypeName:             typenameAPINamespacedResolver,
 		ResolutionRequestTypeName:       typenameNamespacedResolutionRequest,
 		ResolutionResponseTypeName:      typenameNamespacedResolutionResponse,
 		MultiResolutionRequestTypeName:  typenameMultiNamespacedResolutionRequest,
 		MultiResolutionResponseTypeName: typenameMultiNamespacedResolutionResponse,
-	})
+	}, o...)
 }
 
 // NewResolveReferencesCommon returns a NewMethod that writes a ResolveReferences for
-// given managed resource, if needed.
-func NewResolveReferencesCommon(traverser *xptypes.Traverser, receiver, clientPath, referencePkgPath string, names names) New {
-	return func(f *jen.File, o types.Object) {
+// given managed resource, if needed. References in the values of recursive
+// types are resolved by a helper method for each such type. References are
+// resolved in place; references in Spec.InitProvider update its fields and
+// never those of Spec.ForProvider.
+func NewResolveReferencesCommon(traverser *xptypes.Traverser, receiver, clientPath, referencePkgPath string, names names, opts ...ResolveReferencesOption) New {
+	ro := &resolveReferencesOptions{}
+	for _, fn := range opts {
+		fn(ro)
+	}
+	return func(f *jen.File, o types.Object) error {
 		n, ok := o.Type().(*types.Named)
 		if !ok {
-			return
+			return nil
 		}
-		refProcessor := NewReferenceProcessor(receiver,
-			WithDefaultExtractor(jen.Qual(referencePkgPath, "ExternalName").Call()),
-		)
-		cfg := &xptypes.ProcessorConfig{
-			Field: refProcessor,
-			Named: xptypes.NamedProcessorChain{},
+		refs, err := rootReferences(traverser, ro, receiver, referencePkgPath, n)
+		if err != nil {
+			return err
 		}
-		if err := traverser.Traverse(n, cfg); err != nil {
-			panic(errors.Wrapf(err, "cannot traverse the type tree of %s", n.Obj().Name()))
+		hs, err := findHelpers(traverser, ro, receiver, referencePkgPath, refs)
+		if err != nil {
+			return err
 		}
-		refs := refProcessor.GetReferences()
+		refs = hs.prune(refs)
 		if len(refs) == 0 {
-			return
+			return nil
 		}
-		hasMultiResolution := false
-		hasSingleResolution := false
-		resolverCalls := make(jen.Statement, len(refs))
-		for i, ref := range refs {
-			if ref.IsSlice {
-				hasMultiResolution = true
-				resolverCalls[i] = encapsulate(0, multiResolutionCall(ref, referencePkgPath, names.MultiResolutionRequestTypeName), ref.GoValueFieldPath...).Line()
-			} else {
-				hasSingleResolution = true
-				resolverCalls[i] = encapsulate(0, singleResolutionCall(ref, referencePkgPath, names.ResolutionRequestTypeName), ref.GoValueFieldPath...).Line()
+
+		f.Commentf("ResolveReferences of this %s.", o.Name())
+		body := []jen.Code{
+			jen.Id("r").Op(":=").Qual(referencePkgPath, names.APIResolverFunctionName).Call(jen.Id("c"), jen.Id(receiver)),
+			jen.Line(),
+		}
+		body = append(body, resolutions(refs, receiver, referencePkgPath, names)...)
+		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("ResolveReferences").Params(jen.Id("ctx").Qual("context", "Context"), jen.Id("c").Qual(clientPath, "Reader")).Error().Block(
+			append(body, jen.Return(jen.Nil()))...,
+		)
+
+		for _, h := range hs {
+			f.Commentf("%s resolves the references of a %s of this %s.", h.name(), h.typ.Obj().Name(), o.Name())
+			body := []jen.Code{
+				jen.If(jen.Id(helperRoot).Op("==").Nil()).Block(jen.Return(jen.Nil())),
+				jen.Line(),
 			}
+			body = append(body, resolutions(hs.prune(h.refs), receiver, referencePkgPath, names)...)
+			f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id(h.name()).Params(
+				jen.Id("ctx").Qual("context", "Context"),
+				jen.Id("r").Op("*").Qual(referencePkgPath, names.APIResolverTypeName),
+				jen.Id(helperRoot).Op("*").Add(typeCode(h.typ)),
+			).Error().Block(append(body, jen.Return(jen.Nil()))...)
+		}
+		return nil
+	}
+}
+
+// helperRoot is the name of the parameter of helper methods that holds the
+// value whose references they resolve.
+const helperRoot = "v"
+
+// rootReferences returns the references of the supplied managed resource that
+// are within its configured roots.
+func rootReferences(traverser *xptypes.Traverser, ro *resolveReferencesOptions, receiver, referencePkgPath string, n *types.Named) ([]Reference, error) {
+	if len(ro.roots) == 0 {
+		return references(traverser, ro, receiver, receiver, referencePkgPath, n)
+	}
+	if ro.rejectOutside {
+		if err := rejectOutsideRoots(traverser, ro.roots, receiver, referencePkgPath, n); err != nil {
+			return nil, err
 		}
-		var initStatements jen.Statement
-		if hasSingleResolution {
-			initStatements = append(initStatements, jen.Var().Id("rsp").Qual(referencePkgPath, names.ResolutionResponseTypeName))
+	}
+	refs := make([]Reference, 0)
+	for _, root := range ro.roots {
+		rt, path := xptypes.Field(n, root)
+		if rt == nil {
+			return nil, errors.Errorf("cannot resolve references in %s: type %s has no such struct field", root, n.Obj().Name())
 		}
-		if hasMultiResolution {
-			initStatements = append(initStatements, jen.Line().Var().Id("mrsp").Qual(referencePkgPath, names.MultiResolutionResponseTypeName))
+		r, err := references(traverser, ro, receiver, receiver, referencePkgPath, rt, path...)
+		if err != nil {
+			return nil, err
 		}
+		refs = append(refs, r...)
+	}
+	return refs, nil
+}
 
-		f.Commentf("ResolveReferences of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("ResolveReferences").Params(jen.Id("ctx").Qual("context", "Context"), jen.Id("c").Qual(clientPath, "Reader")).Error().Block(
-			jen.Id("r").Op(":=").Qual(referencePkgPath, names.APIResolverFunctionName).Call(jen.Id("c"), jen.Id(receiver)),
-			jen.Line(),
-			&initStatements,
-			jen.Var().Err().Error(),
+// rejectOutsideRoots returns an error if the supplied managed resource has
+// references outside the supplied roots.
+func rejectOutsideRoots(traverser *xptypes.Traverser, roots []string, receiver, referencePkgPath string, n *types.Named) error {
+	// Helpers of nested types would hide where their references are, so we
+	// only use them for recursive types.
+	ro := &resolveReferencesOptions{}
+	refs, err := references(traverser, ro, receiver, receiver, referencePkgPath, n)
+	if err != nil {
+		return err
+	}
+	hs, err := findHelpers(traverser, ro, receiver, referencePkgPath, refs)
+	if err != nil {
+		return err
+	}
+	for _, ref := range hs.prune(refs) {
+		path := make([]string, len(ref.GoValueFieldPath)-1)
+		for i, f := range ref.GoValueFieldPath[1:] {
+			path[i] = fieldCleaner.Replace(f)
+		}
+		p := strings.Join(path, ".")
+		if !slices.ContainsFunc(roots, func(root string) bool { return p == root || strings.HasPrefix(p, root+".") }) {
+			return errors.Errorf("cannot resolve references of field %s: references are only resolved in %s", p, strings.Join(roots, ", "))
+		}
+	}
+	return nil
+}
+
+// references returns the references of the supplied type, with field paths
+// that start from the supplied root and parent fields.
+func references(traverser *xptypes.Traverser, ro *resolveReferencesOptions, receiver, root, referencePkgPath string, n *types.Named, parentFields ...string) ([]Reference, error) {
+	refProcessor := NewReferenceProcessor(receiver,
+		WithRoot(root),
+		WithDefaultExtractor(jen.Qual(referencePkgPath, "ExternalName").Call()),
+	)
+	cfg := &xptypes.ProcessorConfig{
+		Field: refProcessor,
+		Named: xptypes.NamedProcessorChain{},
+		Cycle: xptypes.CycleProcessorFn(refProcessor.ProcessHelper),
+	}
+	if ro.typeHelpers {
+		cfg.Nested = xptypes.NestedProcessorFn(refProcessor.ProcessHelper)
+	}
+	if err := traverser.Traverse(n, cfg, parentFields...); err != nil {
+		return nil, errors.Wrapf(err, "cannot traverse the type tree of %s", n.Obj().Name())
+	}
+	return refProcessor.GetReferences(), nil
+}
+
+// resolutions returns the statements that resolve the supplied references.
+func resolutions(refs []Reference, receiver, referencePkgPath string, names names) []jen.Code {
+	hasMultiResolution := false
+	hasSingleResolution := false
+	hasFormattedValue := false
+	resolverCalls := make(jen.Statement, len(refs))
+	for i, ref := range refs {
+		switch {
+		case ref.Helper != nil:
+			resolverCalls[i] = encapsulate(0, 0, helperCall(ref, receiver), slices.Clone(ref.GoValueFieldPath)...).Line()
+		case ref.IsSlice:
+			hasMultiResolution = true
+			resolverCalls[i] = encapsulate(0, 0, multiResolutionCall(ref, referencePkgPath, names.MultiResolutionRequestTypeName), slices.Clone(ref.GoValueFieldPath)...).Line()
+		default:
+			hasSingleResolution = true
+			hasFormattedValue = hasFormattedValue || (!ref.IsPointer && ref.ValueType != ValueTypeString)
+			resolverCalls[i] = encapsulate(0, 0, singleResolutionCall(ref, referencePkgPath, names.ResolutionRequestTypeName), slices.Clone(ref.GoValueFieldPath)...).Line()
+		}
+	}
+	var initStatements jen.Statement
+	if hasSingleResolution {
+		initStatements = append(initStatements, jen.Var().Id("rsp").Qual(referencePkgPath, names.ResolutionResponseTypeName))
+	}
+	if hasMultiResolution {
+		initStatements = append(initStatements, jen.Line().Var().Id("mrsp").Qual(referencePkgPath, names.MultiResolutionResponseTypeName))
+	}
+	if hasFormattedValue {
+		initStatements = append(initStatements, jen.Line().Var().Id(currentValue).String())
+	}
+	return []jen.Code{
+		&initStatements,
+		jen.Var().Err().Error(),
+		jen.Line(),
+		&resolverCalls,
+		jen.Line(),
+	}
+}
+
+// A helper method that resolves the references of a recursive or nested type.
+type helper struct {
+	typ  *types.Named
+	refs []Reference
+}
+
+// name returns the name of the helper method.
+func (h *helper) name() string {
+	return "resolve" + h.typ.Obj().Name() + "References"
+}
+
+type helpers []*helper
+
+// findHelpers returns the helpers that the supplied references call, and the
+// helpers that they call, in the order they're first called. Only helpers that
+// resolve references, themselves or by calling other helpers, are returned.
+func findHelpers(traverser *xptypes.Traverser, ro *resolveReferencesOptions, receiver, referencePkgPath string, refs []Reference) (helpers, error) {
+	hs := helpers{}
+	queue := slices.Clone(refs)
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if ref.Helper == nil || hs.get(ref.Helper) != nil {
+			continue
+		}
+		h := &helper{typ: ref.Helper}
+		var err error
+		h.refs, err = references(traverser, ro, receiver, helperRoot, referencePkgPath, h.typ)
+		if err != nil {
+			return nil, err
+		}
+		hs = append(hs, h)
+		queue = append(queue, h.refs...)
+	}
+
+	// A helper is useful if it resolves a reference, or calls a useful
+	// helper. Helpers may call each other, so we iterate until no more
+	// helpers are found to be useful.
+	useful := map[*helper]bool{}
+	for changed := true; changed; {
+		changed = false
+		for _, h := range hs {
+			if useful[h] || !slices.ContainsFunc(h.refs, func(r Reference) bool { return r.Helper == nil || useful[hs.get(r.Helper)] }) {
+				continue
+			}
+			useful[h] = true
+			changed = true
+		}
+	}
+	hs = slices.DeleteFunc(hs, func(h *helper) bool { return !useful[h] })
+
+	// Helpers are named after their type, so types in different packages
+	// that share a name can't both have one.
+	for i, h := range hs {
+		if slices.ContainsFunc(hs[:i], func(o *helper) bool { return o.name() == h.name() }) {
+			return nil, errors.Errorf("cannot write helper methods that resolve references: more than one type is named %s", h.typ.Obj().Name())
+		}
+	}
+	return hs, nil
+}
+
+// get returns the helper for the supplied type, if any.
+func (hs helpers) get(n *types.Named) *helper {
+	for _, h := range hs {
+		if types.Identical(h.typ, n) {
+			return h
+		}
+	}
+	return nil
+}
+
+// prune returns the supplied references without calls of helpers that aren't
+// among these helpers.
+func (hs helpers) prune(refs []Reference) []Reference {
+	return slices.DeleteFunc(slices.Clone(refs), func(r Reference) bool { return r.Helper != nil && hs.get(r.Helper) == nil })
+}
+
+// helperCall returns a function that writes a call of the helper method that
+// resolves the references of a recursive or nested value.
+func helperCall(ref Reference, receiver string) resolutionCallFn {
+	return func(fields ...string) *jen.Statement {
+		path := jen.Id(fields[0])
+		for _, f := range fields[1:] {
+			path = path.Dot(f)
+		}
+		// Helpers take a pointer to the value. Fields that are pointers, or
+		// are slices or maps of pointers, have a * in their prefix.
+		if !strings.Contains(ref.GoValueFieldPath[len(ref.GoValueFieldPath)-1], "*") {
+			path = jen.Op("&").Add(path)
+		}
+		h := &helper{typ: ref.Helper}
+		return &jen.Statement{
+			jen.Err().Op("=").Id(receiver).Dot(h.name()).Call(jen.Id("ctx"), jen.Id("r"), path),
 			jen.Line(),
-			&resolverCalls,
+			jen.If(jen.Err().Op("!=").Nil()).Block(
+				jen.Return(jen.Qual("github.com/pkg/errors", "Wrap").Call(jen.Err(), jen.Lit(errorPath(ref)))),
+			),
 			jen.Line(),
-			jen.Return(jen.Nil()),
-		)
+		}
 	}
 }
 
 type resolutionCallFn func(parentFields ...string) *jen.Statement
 
+var fieldCleaner = strings.NewReplacer("map[]", "", "[]", "", "*", "")
+
 // encapsulate goes through the fields and encapsulates the final call with nil
-// guard and/or for loops.
-func encapsulate(index int, callFn resolutionCallFn, fields ...string) *jen.Statement {
-	cleaner := strings.NewReplacer("[]", "", "*", "")
+// guard and/or for loops. The path to the current value starts at the root
+// field; any fields before it lead to a map value that was copied to the root.
+func encapsulate(root, index int, callFn resolutionCallFn, fields ...string) *jen.Statement {
 	if len(fields) <= index {
-		return callFn(fields...)
+		return callFn(fields[root:]...)
 	}
 	field := fields[index]
-	fieldPath := jen.Id(cleaner.Replace(fields[0]))
-	for i := 1; i <= index; i++ {
-		fieldPath = fieldPath.Dot(cleaner.Replace(fields[i]))
+	fieldPath := jen.Id(fieldCleaner.Replace(fields[root]))
+	for i := root + 1; i <= index; i++ {
+		fieldPath = fieldPath.Dot(fieldCleaner.Replace(fields[i]))
 	}
 	switch {
 	case strings.HasPrefix(field, "*"):
-		fields[index] = cleaner.Replace(fields[index])
-		return jen.If(fieldPath.Op("!=").Nil()).Block(encapsulate(index+1, callFn, fields...))
+		fields[index] = fieldCleaner.Replace(fields[index])
+		return jen.If(fieldPath.Op("!=").Nil()).Block(encapsulate(root, index+1, callFn, fields...))
 	case strings.HasPrefix(field, "[]"):
-		fields[index] = cleaner.Replace(fields[index]) + fmt.Sprintf("[i%d]", index)
+		fields[index] = fieldCleaner.Replace(fields[index]) + fmt.Sprintf("[i%d]", index)
 		return jen.For(
 			jen.Id(fmt.Sprintf("i%d", index)).Op(":=").Lit(0),
 			jen.Id(fmt.Sprintf("i%d", index)).Op("<").Len(fieldPath),
 			jen.Id(fmt.Sprintf("i%d", index)).Op("++"),
-		).Block(encapsulate(index+1, callFn, fields...))
+		).Block(encapsulate(root, index+1, callFn, fields...))
+	case strings.HasPrefix(field, "map[]"):
+		// Map values aren't addressable, so we resolve the references of a
+		// copy of each value and store it afterwards. Pointer values can be
+		// updated in place.
+		k, v := jen.Id(fmt.Sprintf("k%d", index)), fmt.Sprintf("v%d", index)
+		fields[index] = v
+		body := []jen.Code{jen.Id(v).Op(":=").Add(fieldPath.Clone()).Index(k)}
+		if strings.HasPrefix(field, "map[]*") {
+			body = append(body, jen.If(jen.Id(v).Op("!=").Nil()).Block(encapsulate(index, index+1, callFn, fields...)))
+		} else {
+			body = append(body, encapsulate(index, index+1, callFn, fields...), fieldPath.Clone().Index(k).Op("=").Id(v))
+		}
+		return jen.For(jen.List(jen.Id("_"), k).Op(":=").Range().Qual("slices", "Sorted").Call(jen.Qual("maps", "Keys").Call(fieldPath))).Block(body...)
 	default:
-		return encapsulate(index+1, callFn, fields...)
+		return encapsulate(root, index+1, callFn, fields...)
 	}
 }
 
+// fieldPaths are the paths to the fields of a reference.
+type fieldPaths struct {
+	value     *jen.Statement
+	reference *jen.Statement
+	selector  *jen.Statement
+}
+
+// pathsTo returns the paths to the fields of the supplied reference, given the
+// path to its current value.
+func pathsTo(ref Reference, fields ...string) fieldPaths {
+	prefixPath := jen.Id(fields[0])
+	for i := 1; i < len(fields)-1; i++ {
+		prefixPath = prefixPath.Dot(fields[i])
+	}
+	return fieldPaths{
+		value:     prefixPath.Clone().Dot(fields[len(fields)-1]),
+		reference: prefixPath.Clone().Dot(ref.GoRefFieldName),
+		selector:  prefixPath.Clone().Dot(ref.GoSelectorFieldName),
+	}
+}
+
+// errorPath returns the path to the current value of the supplied reference
+// that errors are wrapped with.
+func errorPath(ref Reference) string {
+	path := make([]string, len(ref.GoValueFieldPath))
+	for i, f := range ref.GoValueFieldPath {
+		path[i] = fieldCleaner.Replace(f)
+		switch {
+		case strings.HasPrefix(f, "map[]"):
+			path[i] += fmt.Sprintf("[k%d]", i)
+		case strings.HasPrefix(f, "[]"):
+			path[i] += fmt.Sprintf("[i%d]", i)
+		}
+	}
+	if ref.IsMap {
+		path[len(path)-1] += fmt.Sprintf("[k%d]", len(path)-1)
+	}
+	return strings.Join(path, ".")
+}
+
+// forEachKey returns the supplied resolution of a reference. If the reference
+// is a map it returns a loop that resolves the value of each key of the map of
+// references instead, in sorted order, creating the map of values if need be.
+// Map references don't have a selector.
+func forEachKey(ref Reference, p fieldPaths, resolve func(p fieldPaths) *jen.Statement) *jen.Statement {
+	if !ref.IsMap {
+		return resolve(p)
+	}
+	k := jen.Id(fmt.Sprintf("k%d", len(ref.GoValueFieldPath)-1))
+	return jen.For(jen.List(jen.Id("_"), k).Op(":=").Range().Qual("slices", "Sorted").Call(jen.Qual("maps", "Keys").Call(p.reference))).Block(
+		jen.If(p.value.Clone().Op("==").Nil()).Block(
+			p.value.Clone().Op("=").Add(ref.MapType).Values(),
+		),
+		resolve(fieldPaths{value: p.value.Clone().Index(k), reference: p.reference.Clone().Index(k)}),
+	)
+}
+
+// request returns the fields of a resolution request for the supplied
+// reference.
+func request(ref Reference, referencePkgPath string, p fieldPaths) jen.Dict {
+	d := jen.Dict{
+		jen.Id("To"): jen.Qual(referencePkgPath, "To").Values(jen.Dict{
+			jen.Id("Managed"): ref.RemoteType,
+			jen.Id("List"):    ref.RemoteListType,
+		}),
+		jen.Id("Extract"):   ref.Extractor,
+		jen.Id("Namespace"): ref.GetNamespace,
+	}
+	if p.selector != nil {
+		d[jen.Id("Selector")] = p.selector
+	}
+	return d
+}
+
 func singleResolutionCall(ref Reference, referencePkgPath, resolutionRequestTypeName string) resolutionCallFn {
 	return func(fields ...string) *jen.Statement {
-		prefixPath := jen.Id(fields[0])
-		for i := 1; i < len(fields)-1; i++ {
-			prefixPath = prefixPath.Dot(fields[i])
-		}
-		currentValuePath := prefixPath.Clone().Dot(fields[len(fields)-1])
-		referenceFieldPath := prefixPath.Clone().Dot(ref.GoRefFieldName)
-		selectorFieldPath := prefixPath.Clone().Dot(ref.GoSelectorFieldName)
-
-		setResolvedValue := currentValuePath.Clone().Op("=").Id("rsp").Dot("ResolvedValue")
-		toPointerFunction := "ToPtrValue"
-		fromPointerFunction := "FromPtrValue"
-		if ref.IsFloatPointer {
-			toPointerFunction = "ToFloatPtrValue"
-			fromPointerFunction = "FromFloatPtrValue"
-		}
-		if ref.IsPointer {
-			setResolvedValue = currentValuePath.Clone().Op("=").Qual(referencePkgPath, toPointerFunction).Call(jen.Id("rsp").Dot("ResolvedValue"))
-			currentValuePath = jen.Qual(referencePkgPath, fromPointerFunction).Call(currentValuePath)
-		}
-		return &jen.Statement{
-			jen.List(jen.Id("rsp"), jen.Err()).Op("=").Id("r").Dot("Resolve").Call(
-				jen.Id("ctx"),
-				jen.Qual(referencePkgPath, resolutionRequestTypeName).Values(jen.Dict{
-					jen.Id("CurrentValue"): currentValuePath,
-					jen.Id("Reference"):    referenceFieldPath,
-					jen.Id("Selector"):     selectorFieldPath,
-					jen.Id("To"): jen.Qual(referencePkgPath, "To").Values(jen.Dict{
-						jen.Id("Managed"): ref.RemoteType,
-						jen.Id("List"):    ref.RemoteListType,
-					}),
-					jen.Id("Extract"):   ref.Extractor,
-					jen.Id("Namespace"): ref.GetNamespace,
-				},
+		return forEachKey(ref, pathsTo(ref, fields...), func(p fieldPaths) *jen.Statement {
+			wrapErr := jen.If(jen.Err().Op("!=").Nil()).Block(
+				jen.Return(jen.Qual("github.com/pkg/errors", "Wrap").Call(jen.Err(), jen.Lit(errorPath(ref)))),
+			)
+
+			currentValuePath := p.value
+			formatCurrentValue := &jen.Statement{}
+			setResolvedValue := p.value.Clone().Op("=").Id("rsp").Dot("ResolvedValue")
+			switch {
+			case ref.IsPointer:
+				toPointerFunction, fromPointerFunction := pointerFunctions(ref.ValueType)
+				setResolvedValue = p.value.Clone().Op("=").Qual(referencePkgPath, toPointerFunction).Call(jen.Id("rsp").Dot("ResolvedValue"))
+				currentValuePath = jen.Qual(referencePkgPath, fromPointerFunction).Call(p.value)
+			case ref.ValueType != ValueTypeString:
+				// A reference is only resolved if its current value is
+				// empty, so zero values are sent as an empty string rather
+				// than e.g. 0 or false. An empty resolved value leaves
+				// the field unchanged.
+				format, parse := strconvCalls(ref.ValueType, p.value, jen.Id("rsp").Dot("ResolvedValue"))
+				formatCurrentValue = &jen.Statement{
+					jen.Id(currentValue).Op("=").Lit(""),
+					jen.Line(),
+					jen.If(isSet(ref.ValueType, p.value)).Block(jen.Id(currentValue).Op("=").Add(format)),
+					jen.Line(),
+				}
+				setResolvedValue = jen.If(jen.Id("rsp").Dot("ResolvedValue").Op("!=").Lit("")).Block(
+					jen.List(p.value.Clone(), jen.Err()).Op("=").Add(parse),
+					wrapErr.Clone(),
+				)
+				currentValuePath = jen.Id(currentValue)
+			}
+
+			req := request(ref, referencePkgPath, p)
+			req[jen.Id("CurrentValue")] = currentValuePath
+			req[jen.Id("Reference")] = p.reference
+			return &jen.Statement{
+				formatCurrentValue,
+				jen.List(jen.Id("rsp"), jen.Err()).Op("=").Id("r").Dot("Resolve").Call(
+					jen.Id("ctx"),
+					jen.Qual(referencePkgPath, resolutionRequestTypeName).Values(req),
 				),
-			),
-			jen.Line(),
-			jen.If(jen.Err().Op("!=").Nil()).Block(
-				jen.Return(jen.Qual("github.com/pkg/errors", "Wrap").Call(jen.Err(), jen.Lit(strings.Join(ref.GoValueFieldPath, ".")))),
-			),
-			jen.Line(),
-			setResolvedValue,
-			jen.Line(),
-			referenceFieldPath.Clone().Op("=").Id("rsp").Dot("ResolvedReference"),
-			jen.Line(),
-		}
+				jen.Line(),
+				wrapErr,
+				jen.Line(),
+				setResolvedValue,
+				jen.Line(),
+				p.reference.Clone().Op("=").Id("rsp").Dot("ResolvedReference"),
+				jen.Line(),
+			}
+		})
 	}
 }
 
 func multiResolutionCall(ref Reference, referencePkgPath, multiResolutionRequestTypeName string) resolutionCallFn {
 	return func(fields ...string) *jen.Statement {
-		prefixPath := jen.Id(fields[0])
-		for i := 1; i < len(fields)-1; i++ {
-			prefixPath = prefixPath.Dot(fields[i])
-		}
-		currentValuePath := prefixPath.Clone().Dot(fields[len(fields)-1])
-		referenceFieldPath := prefixPath.Clone().Dot(ref.GoRefFieldName)
-		selectorFieldPath := prefixPath.Clone().Dot(ref.GoSelectorFieldName)
-
-		setResolvedValues := currentValuePath.Clone().Op("=").Id("mrsp").Dot("ResolvedValues")
-		toPointersFunction := "ToPtrValues"
-		fromPointersFunction := "FromPtrValues"
-		if ref.IsFloatPointer {
-			toPointersFunction = "ToFloatPtrValues"
-			fromPointersFunction = "FromFloatPtrValues"
-		}
-
-		if ref.IsPointer {
-			setResolvedValues = currentValuePath.Clone().Op("=").Qual(referencePkgPath, toPointersFunction).Call(jen.Id("mrsp").Dot("ResolvedValues"))
-			currentValuePath = jen.Qual(referencePkgPath, fromPointersFunction).Call(currentValuePath)
-		}
+		return forEachKey(ref, pathsTo(ref, fields...), func(p fieldPaths) *jen.Statement {
+			currentValuePath := p.value
+			setResolvedValues := p.value.Clone().Op("=").Id("mrsp").Dot("ResolvedValues")
+			if ref.IsPointer {
+				toPointerFunction, fromPointerFunction := pointerFunctions(ref.ValueType)
+				setResolvedValues = p.value.Clone().Op("=").Qual(referencePkgPath, toPointerFunction+"s").Call(jen.Id("mrsp").Dot("ResolvedValues"))
+				currentValuePath = jen.Qual(referencePkgPath, fromPointerFunction+"s").Call(p.value)
+			}
 
-		return &jen.Statement{
-			jen.List(jen.Id("mrsp"), jen.Err()).Op("=").Id("r").Dot("ResolveMultiple").Call(
-				jen.Id("ctx"),
-				jen.Qual(referencePkgPath, multiResolutionRequestTypeName).Values(jen.Dict{
-					jen.Id("CurrentValues"): currentValuePath,
-					jen.Id("References"):    referenceFieldPath,
-					jen.Id("Selector"):      selectorFieldPath,
-					jen.Id("To"): jen.Qual(referencePkgPath, "To").Values(jen.Dict{
-						jen.Id("Managed"): ref.RemoteType,
-						jen.Id("List"):    ref.RemoteListType,
-					}),
-					jen.Id("Extract"):   ref.Extractor,
-					jen.Id("Namespace"): ref.GetNamespace,
-				},
+			req := request(ref, referencePkgPath, p)
+			req[jen.Id("CurrentValues")] = currentValuePath
+			req[jen.Id("References")] = p.reference
+			return &jen.Statement{
+				jen.List(jen.Id("mrsp"), jen.Err()).Op("=").Id("r").Dot("ResolveMultiple").Call(
+					jen.Id("ctx"),
+					jen.Qual(referencePkgPath, multiResolutionRequestTypeName).Values(req),
 				),
-			),
-			jen.Line(),
-			jen.If(jen.Err().Op("!=").Nil()).Block(
-				jen.Return(jen.Qual("github.com/pkg/errors", "Wrap").Call(jen.Err(), jen.Lit(strings.Join(ref.GoValueFieldPath, ".")))),
-			),
-			jen.Line(),
-			setResolvedValues,
-			jen.Line(),
-			referenceFieldPath.Clone().Op("=").Id("mrsp").Dot("ResolvedReferences"),
-			jen.Line(),
-		}
+				jen.Line(),
+				jen.If(jen.Err().Op("!=").Nil()).Block(
+					jen.Return(jen.Qual("github.com/pkg/errors", "Wrap").Call(jen.Err(), jen.Lit(errorPath(ref)))),
+				),
+				jen.Line(),
+				setResolvedValues,
+				jen.Line(),
+				p.reference.Clone().Op("=").Id("mrsp").Dot("ResolvedReferences"),
+				jen.Line(),
+			}
+		})
+	}
+}
+
+// pointerFunctions returns the names of the functions of the reference package
+// that convert a pointer to a value of the supplied type to and from a string,
+// for example ToIntPtrValue and FromIntPtrValue. Appending an s returns the
+// names of the functions that convert slices of pointers.
+func pointerFunctions(vt ValueType) (to, from string) {
+	kind := map[ValueType]string{ValueTypeInt: "Int", ValueTypeFloat: "Float"}[vt]
+	return "To" + kind + "PtrValue", "From" + kind + "PtrValue"
+}
+
+// currentValue is the name of the variable that holds the current value of a
+// field that isn't a string, formatted as a string.
+const currentValue = "current"
+
+// isSet returns a condition that is true if the supplied value of the supplied
+// type isn't its zero value.
+func isSet(vt ValueType, v *jen.Statement) *jen.Statement {
+	if vt == ValueTypeBool {
+		return v.Clone()
+	}
+	return v.Clone().Op("!=").Lit(0)
+}
+
+// strconvCalls returns calls that format the supplied value of the supplied
+// type as a string, and parse it from the supplied string.
+func strconvCalls(vt ValueType, v, str jen.Code) (format, parse *jen.Statement) {
+	switch vt {
+	case ValueTypeInt:
+		return jen.Qual("strconv", "FormatInt").Call(v, jen.Lit(10)), jen.Qual("strconv", "ParseInt").Call(str, jen.Lit(10), jen.Lit(64))
+	case ValueTypeFloat:
+		return jen.Qual("strconv", "FormatFloat").Call(v, jen.LitRune('f'), jen.Lit(-1), jen.Lit(64)), jen.Qual("strconv", "ParseFloat").Call(str, jen.Lit(64))
+	case ValueTypeBool:
+		return jen.Qual("strconv", "FormatBool").Call(v), jen.Qual("strconv", "ParseBool").Call(str)
+	case ValueTypeString:
 	}
+	return jen.Add(v), jen.Add(str)
 }
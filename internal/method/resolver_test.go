@@ -18,6 +18,10 @@ package method
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/dave/jennifer/jen"
@@ -72,7 +76,7 @@ type ModelParameters struct {
 
 	// +crossplane:generate:reference:type=golang.org/fake/v1alpha1.Configuration
 	// +crossplane:generate:reference:extractor=golang.org/fake/v1alpha1.Configuration()
-	CustomConfiguration *Configuration
+	CustomConfiguration *string
 
 	// +crossplane:generate:reference:type=github.com/crossplane/provider-aws/apis/identity/v1beta1.IAM
 	// +crossplane:generate:reference:extractor=Count()
@@ -583,7 +587,9 @@ func TestNewResolveReferences(t *testing.T) {
 		t.Error(err)
 	}
 	f := jen.NewFilePath("golang.org/fake/v1alpha1")
-	NewResolveReferences(xptypes.NewTraverser(comments.In(pkgs[0])), "mg", "example.org/client", "example.org/reference")(f, pkgs[0].Types.Scope().Lookup("Model"))
+	if err := NewResolveReferences(xptypes.NewTraverser(comments.In(pkgs[0])), "mg", "example.org/client", "example.org/reference")(f, pkgs[0].Types.Scope().Lookup("Model")); err != nil {
+		t.Fatal(err)
+	}
 	if diff := cmp.Diff(generated, fmt.Sprintf("%#v", f)); diff != "" {
 		t.Errorf("NewResolveReferences(): -want, +got\n%s", diff)
 	}
@@ -603,8 +609,1187 @@ func TestNewResolveReferencesV2(t *testing.T) {
 		t.Error(err)
 	}
 	f := jen.NewFilePath("golang.org/fake/v1alpha1")
-	NewResolveReferencesV2(xptypes.NewTraverser(comments.In(pkgs[0])), "mg", "example.org/client", "example.org/reference")(f, pkgs[0].Types.Scope().Lookup("Model"))
+	if err := NewResolveReferencesV2(xptypes.NewTraverser(comments.In(pkgs[0])), "mg", "example.org/client", "example.org/reference")(f, pkgs[0].Types.Scope().Lookup("Model")); err != nil {
+		t.Fatal(err)
+	}
 	if diff := cmp.Diff(generatedNamespaced, fmt.Sprintf("%#v", f)); diff != "" {
 		t.Errorf("NewResolveReferences(): -want, +got\n%s", diff)
 	}
 }
+
+func TestNewResolveReferencesInvalidExtractor(t *testing.T) {
+	exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{{
+		Name: "golang.org/fake",
+		Files: map[string]any{
+			"v1alpha1/model.go": `
+package v1alpha1
+
+type ModelParameters struct {
+	// +crossplane:generate:reference:type=SecurityGroup
+	// +crossplane:generate:reference:extractor=NotAFunction
+	SecurityGroupID string
+}
+
+type ModelSpec struct {
+	ForProvider ModelParameters
+}
+
+type Model struct {
+	Spec ModelSpec
+}
+`,
+		},
+	}})
+	defer exported.Cleanup()
+	exported.Config.Mode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax
+	pkgs, err := packages.Load(exported.Config, fmt.Sprintf("file=%s", exported.File("golang.org/fake", "v1alpha1/model.go")))
+	if err != nil {
+		t.Error(err)
+	}
+	f := jen.NewFilePath("golang.org/fake/v1alpha1")
+	err = NewResolveReferences(xptypes.NewTraverser(comments.In(pkgs[0])), "mg", "example.org/client", "example.org/reference")(f, pkgs[0].Types.Scope().Lookup("Model"))
+	if err == nil || !strings.Contains(err.Error(), `path "NotAFunction" is not a valid function code`) {
+		t.Errorf("NewResolveReferences(): want invalid extractor error, got %v", err)
+	}
+}
+
+func TestNewResolveReferencesValueTypes(t *testing.T) {
+	exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{{
+		Name: "golang.org/fake",
+		Files: map[string]any{
+			"v1alpha1/model.go": `
+package v1alpha1
+
+type ModelParameters struct {
+	// +crossplane:generate:reference:type=Port
+	PortNumber *int64
+
+	// +crossplane:generate:reference:type=Port
+	PortNumbers []*int64
+
+	// +crossplane:generate:reference:type=Volume
+	Size int64
+
+	// +crossplane:generate:reference:type=Weight
+	Weight float64
+
+	// +crossplane:generate:reference:type=Feature
+	Enabled bool
+}
+
+type ModelSpec struct {
+	ForProvider ModelParameters
+}
+
+type Model struct {
+	Spec ModelSpec
+}
+`,
+		},
+	}})
+	defer exported.Cleanup()
+	exported.Config.Mode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax
+	pkgs, err := packages.Load(exported.Config, fmt.Sprintf("file=%s", exported.File("golang.org/fake", "v1alpha1/model.go")))
+	if err != nil {
+		t.Error(err)
+	}
+	f := jen.NewFilePath("golang.org/fake/v1alpha1")
+	if err := NewResolveReferences(xptypes.NewTraverser(comments.In(pkgs[0])), "mg", "example.org/client", "example.org/reference")(f, pkgs[0].Types.Scope().Lookup("Model")); err != nil {
+		t.Fatal(err)
+	}
+	want := `package v1alpha1
+
+import (
+	"context"
+	client "example.org/client"
+	reference "example.org/reference"
+	errors "github.com/pkg/errors"
+	"strconv"
+)
+
+// ResolveReferences of this Model.
+func (mg *Model) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	var rsp reference.ResolutionResponse
+	var mrsp reference.MultiResolutionResponse
+	var current string
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromIntPtrValue(mg.Spec.ForProvider.PortNumber),
+		Extract:      reference.ExternalName(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.ForProvider.PortNumberRef,
+		Selector:     mg.Spec.ForProvider.PortNumberSelector,
+		To: reference.To{
+			List:    &PortList{},
+			Managed: &Port{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.PortNumber")
+	}
+	mg.Spec.ForProvider.PortNumber = reference.ToIntPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.PortNumberRef = rsp.ResolvedReference
+
+	mrsp, err = r.ResolveMultiple(ctx, reference.MultiResolutionRequest{
+		CurrentValues: reference.FromIntPtrValues(mg.Spec.ForProvider.PortNumbers),
+		Extract:       reference.ExternalName(),
+		Namespace:     mg.GetNamespace(),
+		References:    mg.Spec.ForProvider.PortNumbersRefs,
+		Selector:      mg.Spec.ForProvider.PortNumbersSelector,
+		To: reference.To{
+			List:    &PortList{},
+			Managed: &Port{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.PortNumbers")
+	}
+	mg.Spec.ForProvider.PortNumbers = reference.ToIntPtrValues(mrsp.ResolvedValues)
+	mg.Spec.ForProvider.PortNumbersRefs = mrsp.ResolvedReferences
+
+	current = ""
+	if mg.Spec.ForProvider.Size != 0 {
+		current = strconv.FormatInt(mg.Spec.ForProvider.Size, 10)
+	}
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: current,
+		Extract:      reference.ExternalName(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.ForProvider.SizeRef,
+		Selector:     mg.Spec.ForProvider.SizeSelector,
+		To: reference.To{
+			List:    &VolumeList{},
+			Managed: &Volume{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.Size")
+	}
+	if rsp.ResolvedValue != "" {
+		mg.Spec.ForProvider.Size, err = strconv.ParseInt(rsp.ResolvedValue, 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.ForProvider.Size")
+		}
+	}
+	mg.Spec.ForProvider.SizeRef = rsp.ResolvedReference
+
+	current = ""
+	if mg.Spec.ForProvider.Weight != 0 {
+		current = strconv.FormatFloat(mg.Spec.ForProvider.Weight, 'f', -1, 64)
+	}
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: current,
+		Extract:      reference.ExternalName(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.ForProvider.WeightRef,
+		Selector:     mg.Spec.ForProvider.WeightSelector,
+		To: reference.To{
+			List:    &WeightList{},
+			Managed: &Weight{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.Weight")
+	}
+	if rsp.ResolvedValue != "" {
+		mg.Spec.ForProvider.Weight, err = strconv.ParseFloat(rsp.ResolvedValue, 64)
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.ForProvider.Weight")
+		}
+	}
+	mg.Spec.ForProvider.WeightRef = rsp.ResolvedReference
+
+	current = ""
+	if mg.Spec.ForProvider.Enabled {
+		current = strconv.FormatBool(mg.Spec.ForProvider.Enabled)
+	}
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: current,
+		Extract:      reference.ExternalName(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.ForProvider.EnabledRef,
+		Selector:     mg.Spec.ForProvider.EnabledSelector,
+		To: reference.To{
+			List:    &FeatureList{},
+			Managed: &Feature{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.Enabled")
+	}
+	if rsp.ResolvedValue != "" {
+		mg.Spec.ForProvider.Enabled, err = strconv.ParseBool(rsp.ResolvedValue)
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.ForProvider.Enabled")
+		}
+	}
+	mg.Spec.ForProvider.EnabledRef = rsp.ResolvedReference
+
+	return nil
+}
+`
+	if diff := cmp.Diff(want, fmt.Sprintf("%#v", f)); diff != "" {
+		t.Errorf("NewResolveReferences(): -want, +got\n%s", diff)
+	}
+}
+
+// TestNewResolveReferencesUnsetValues runs generated code against a resolver
+// that, like crossplane-runtime's, skips references whose current value isn't
+// empty.
+func TestNewResolveReferencesUnsetValues(t *testing.T) {
+	exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{
+		{
+			Name: "golang.org/fake",
+			Files: map[string]any{
+				"v1alpha1/model.go": `
+package v1alpha1
+
+import "example.org/reference"
+
+type ModelParameters struct {
+	// +crossplane:generate:reference:type=Volume
+	Size         int64
+	SizeRef      *reference.Reference
+	SizeSelector *reference.Selector
+
+	// +crossplane:generate:reference:type=Volume
+	Weight         float64
+	WeightRef      *reference.Reference
+	WeightSelector *reference.Selector
+
+	// +crossplane:generate:reference:type=Feature
+	Enabled         bool
+	EnabledRef      *reference.Reference
+	EnabledSelector *reference.Selector
+}
+
+type ModelSpec struct {
+	ForProvider ModelParameters
+}
+
+type Model struct {
+	Spec ModelSpec
+}
+
+func (mg *Model) GetNamespace() string { return "" }
+
+type Volume struct{}
+type VolumeList struct{}
+type Feature struct{}
+type FeatureList struct{}
+`,
+				"v1alpha1/model_test.go": `
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"example.org/reference"
+)
+
+func TestResolveReferences(t *testing.T) {
+	reference.Values = map[string]string{"size": "42", "weight": "0.5", "enabled": "true"}
+	mg := &Model{Spec: ModelSpec{ForProvider: ModelParameters{
+		SizeRef:    &reference.Reference{Name: "size"},
+		Weight:     1.5,
+		WeightRef:  &reference.Reference{Name: "weight"},
+		EnabledRef: &reference.Reference{Name: "enabled"},
+	}}}
+	if err := mg.ResolveReferences(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := mg.Spec.ForProvider; got.Size != 42 || got.Weight != 1.5 || !got.Enabled {
+		t.Errorf("ResolveReferences(): want Size 42, Weight 1.5 and Enabled true, got %+v", got)
+	}
+}
+`,
+			},
+		},
+		{
+			Name: "example.org",
+			Files: map[string]any{
+				"client/client.go": "package client\n\ntype Reader interface{}\n",
+				"reference/reference.go": `
+package reference
+
+import "context"
+
+var Values map[string]string
+
+type Reference struct{ Name string }
+type Selector struct{}
+type To struct{ Managed, List interface{} }
+type ExtractValueFn func(interface{}) string
+
+func ExternalName() ExtractValueFn { return nil }
+
+type ResolutionRequest struct {
+	CurrentValue string
+	Reference    *Reference
+	Selector     *Selector
+	To           To
+	Extract      ExtractValueFn
+	Namespace    string
+}
+
+type ResolutionResponse struct {
+	ResolvedValue     string
+	ResolvedReference *Reference
+}
+
+type APIResolver struct{}
+
+func NewAPIResolver(_, _ interface{}) *APIResolver { return &APIResolver{} }
+
+func (r *APIResolver) Resolve(_ context.Context, req ResolutionRequest) (ResolutionResponse, error) {
+	if req.CurrentValue != "" || req.Reference == nil {
+		return ResolutionResponse{ResolvedValue: req.CurrentValue, ResolvedReference: req.Reference}, nil
+	}
+	return ResolutionResponse{ResolvedValue: Values[req.Reference.Name], ResolvedReference: req.Reference}, nil
+}
+`,
+			},
+		},
+		{
+			Name:  "github.com/pkg/errors",
+			Files: map[string]any{"errors.go": "package errors\n\nfunc Wrap(err error, _ string) error { return err }\n"},
+		},
+	})
+	defer exported.Cleanup()
+	exported.Config.Mode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax
+	model := exported.File("golang.org/fake", "v1alpha1/model.go")
+	pkgs, err := packages.Load(exported.Config, fmt.Sprintf("file=%s", model))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := jen.NewFilePath("golang.org/fake/v1alpha1")
+	if err := NewResolveReferences(xptypes.NewTraverser(comments.In(pkgs[0])), "mg", "example.org/client", "example.org/reference")(f, pkgs[0].Types.Scope().Lookup("Model")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(filepath.Dir(model), "zz_generated.resolvers.go"), []byte(fmt.Sprintf("%#v", f)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", "test", "./v1alpha1")
+	cmd.Dir = exported.Config.Dir
+	cmd.Env = append(exported.Config.Env, "GOFLAGS=-mod=mod")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("generated ResolveReferences: %v\n%s", err, out)
+	}
+}
+
+func TestNewResolveReferencesMaps(t *testing.T) {
+	exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{{
+		Name: "golang.org/fake",
+		Files: map[string]any{
+			"v1alpha1/model.go": `
+package v1alpha1
+
+type SubnetSpec struct {
+	// +crossplane:generate:reference:type=Subnet
+	SubnetID string
+
+	// +crossplane:generate:reference:type=SecurityGroup
+	SecurityGroupIDs []string
+}
+
+type RouteSpec struct {
+	// +crossplane:generate:reference:type=RouteTable
+	RouteTableID *string
+}
+
+type ModelParameters struct {
+	Subnets map[string]SubnetSpec
+
+	Routes map[string]*RouteSpec
+
+	// +crossplane:generate:reference:type=Subnet
+	Tags map[string]string
+
+	// +crossplane:generate:reference:type=SecurityGroup
+	Groups map[string][]*string
+}
+
+type ModelSpec struct {
+	ForProvider ModelParameters
+}
+
+type Model struct {
+	Spec ModelSpec
+}
+`,
+		},
+	}})
+	defer exported.Cleanup()
+	exported.Config.Mode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax
+	pkgs, err := packages.Load(exported.Config, fmt.Sprintf("file=%s", exported.File("golang.org/fake", "v1alpha1/model.go")))
+	if err != nil {
+		t.Error(err)
+	}
+	f := jen.NewFilePath("golang.org/fake/v1alpha1")
+	if err := NewResolveReferences(xptypes.NewTraverser(comments.In(pkgs[0])), "mg", "example.org/client", "example.org/reference")(f, pkgs[0].Types.Scope().Lookup("Model")); err != nil {
+		t.Fatal(err)
+	}
+	want := `package v1alpha1
+
+import (
+	"context"
+	client "example.org/client"
+	reference "example.org/reference"
+	errors "github.com/pkg/errors"
+	"maps"
+	"slices"
+)
+
+// ResolveReferences of this Model.
+func (mg *Model) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	var rsp reference.ResolutionResponse
+	var mrsp reference.MultiResolutionResponse
+	var err error
+
+	for _, k3 := range slices.Sorted(maps.Keys(mg.Spec.ForProvider.Subnets)) {
+		v3 := mg.Spec.ForProvider.Subnets[k3]
+		rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+			CurrentValue: v3.SubnetID,
+			Extract:      reference.ExternalName(),
+			Namespace:    mg.GetNamespace(),
+			Reference:    v3.SubnetIDRef,
+			Selector:     v3.SubnetIDSelector,
+			To: reference.To{
+				List:    &SubnetList{},
+				Managed: &Subnet{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.ForProvider.Subnets[k3].SubnetID")
+		}
+		v3.SubnetID = rsp.ResolvedValue
+		v3.SubnetIDRef = rsp.ResolvedReference
+
+		mg.Spec.ForProvider.Subnets[k3] = v3
+	}
+	for _, k3 := range slices.Sorted(maps.Keys(mg.Spec.ForProvider.Subnets)) {
+		v3 := mg.Spec.ForProvider.Subnets[k3]
+		mrsp, err = r.ResolveMultiple(ctx, reference.MultiResolutionRequest{
+			CurrentValues: v3.SecurityGroupIDs,
+			Extract:       reference.ExternalName(),
+			Namespace:     mg.GetNamespace(),
+			References:    v3.SecurityGroupIDsRefs,
+			Selector:      v3.SecurityGroupIDsSelector,
+			To: reference.To{
+				List:    &SecurityGroupList{},
+				Managed: &SecurityGroup{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.ForProvider.Subnets[k3].SecurityGroupIDs")
+		}
+		v3.SecurityGroupIDs = mrsp.ResolvedValues
+		v3.SecurityGroupIDsRefs = mrsp.ResolvedReferences
+
+		mg.Spec.ForProvider.Subnets[k3] = v3
+	}
+	for _, k3 := range slices.Sorted(maps.Keys(mg.Spec.ForProvider.Routes)) {
+		v3 := mg.Spec.ForProvider.Routes[k3]
+		if v3 != nil {
+			rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+				CurrentValue: reference.FromPtrValue(v3.RouteTableID),
+				Extract:      reference.ExternalName(),
+				Namespace:    mg.GetNamespace(),
+				Reference:    v3.RouteTableIDRef,
+				Selector:     v3.RouteTableIDSelector,
+				To: reference.To{
+					List:    &RouteTableList{},
+					Managed: &RouteTable{},
+				},
+			})
+			if err != nil {
+				return errors.Wrap(err, "mg.Spec.ForProvider.Routes[k3].RouteTableID")
+			}
+			v3.RouteTableID = reference.ToPtrValue(rsp.ResolvedValue)
+			v3.RouteTableIDRef = rsp.ResolvedReference
+
+		}
+	}
+	for _, k3 := range slices.Sorted(maps.Keys(mg.Spec.ForProvider.TagsRefs)) {
+		if mg.Spec.ForProvider.Tags == nil {
+			mg.Spec.ForProvider.Tags = map[string]string{}
+		}
+		rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+			CurrentValue: mg.Spec.ForProvider.Tags[k3],
+			Extract:      reference.ExternalName(),
+			Namespace:    mg.GetNamespace(),
+			Reference:    mg.Spec.ForProvider.TagsRefs[k3],
+			To: reference.To{
+				List:    &SubnetList{},
+				Managed: &Subnet{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.ForProvider.Tags[k3]")
+		}
+		mg.Spec.ForProvider.Tags[k3] = rsp.ResolvedValue
+		mg.Spec.ForProvider.TagsRefs[k3] = rsp.ResolvedReference
+
+	}
+	for _, k3 := range slices.Sorted(maps.Keys(mg.Spec.ForProvider.GroupsRefs)) {
+		if mg.Spec.ForProvider.Groups == nil {
+			mg.Spec.ForProvider.Groups = map[string][]*string{}
+		}
+		mrsp, err = r.ResolveMultiple(ctx, reference.MultiResolutionRequest{
+			CurrentValues: reference.FromPtrValues(mg.Spec.ForProvider.Groups[k3]),
+			Extract:       reference.ExternalName(),
+			Namespace:     mg.GetNamespace(),
+			References:    mg.Spec.ForProvider.GroupsRefs[k3],
+			To: reference.To{
+				List:    &SecurityGroupList{},
+				Managed: &SecurityGroup{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.ForProvider.Groups[k3]")
+		}
+		mg.Spec.ForProvider.Groups[k3] = reference.ToPtrValues(mrsp.ResolvedValues)
+		mg.Spec.ForProvider.GroupsRefs[k3] = mrsp.ResolvedReferences
+
+	}
+
+	return nil
+}
+`
+	if diff := cmp.Diff(want, fmt.Sprintf("%#v", f)); diff != "" {
+		t.Errorf("NewResolveReferences(): -want, +got\n%s", diff)
+	}
+}
+
+func TestNewResolveReferencesRecursive(t *testing.T) {
+	exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{{
+		Name: "golang.org/fake",
+		Files: map[string]any{
+			"v1alpha1/model.go": `
+package v1alpha1
+
+type Rule struct {
+	// +crossplane:generate:reference:type=IPSet
+	IPSetID *string
+
+	Rules []Rule
+
+	Not *Rule
+}
+
+type Empty struct {
+	Children []Empty
+}
+
+type ModelParameters struct {
+	Rules []Rule
+
+	Empty Empty
+}
+
+type ModelSpec struct {
+	ForProvider ModelParameters
+}
+
+type Model struct {
+	Spec ModelSpec
+}
+`,
+		},
+	}})
+	defer exported.Cleanup()
+	exported.Config.Mode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax
+	pkgs, err := packages.Load(exported.Config, fmt.Sprintf("file=%s", exported.File("golang.org/fake", "v1alpha1/model.go")))
+	if err != nil {
+		t.Error(err)
+	}
+	f := jen.NewFilePath("golang.org/fake/v1alpha1")
+	if err := NewResolveReferences(xptypes.NewTraverser(comments.In(pkgs[0])), "mg", "example.org/client", "example.org/reference")(f, pkgs[0].Types.Scope().Lookup("Model")); err != nil {
+		t.Error(err)
+	}
+
+	want := `package v1alpha1
+
+import (
+	"context"
+	client "example.org/client"
+	reference "example.org/reference"
+	errors "github.com/pkg/errors"
+)
+
+// ResolveReferences of this Model.
+func (mg *Model) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	var rsp reference.ResolutionResponse
+	var err error
+
+	for i3 := 0; i3 < len(mg.Spec.ForProvider.Rules); i3++ {
+		rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+			CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.Rules[i3].IPSetID),
+			Extract:      reference.ExternalName(),
+			Namespace:    mg.GetNamespace(),
+			Reference:    mg.Spec.ForProvider.Rules[i3].IPSetIDRef,
+			Selector:     mg.Spec.ForProvider.Rules[i3].IPSetIDSelector,
+			To: reference.To{
+				List:    &IPSetList{},
+				Managed: &IPSet{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.ForProvider.Rules[i3].IPSetID")
+		}
+		mg.Spec.ForProvider.Rules[i3].IPSetID = reference.ToPtrValue(rsp.ResolvedValue)
+		mg.Spec.ForProvider.Rules[i3].IPSetIDRef = rsp.ResolvedReference
+
+	}
+	for i3 := 0; i3 < len(mg.Spec.ForProvider.Rules); i3++ {
+		for i4 := 0; i4 < len(mg.Spec.ForProvider.Rules[i3].Rules); i4++ {
+			err = mg.resolveRuleReferences(ctx, r, &mg.Spec.ForProvider.Rules[i3].Rules[i4])
+			if err != nil {
+				return errors.Wrap(err, "mg.Spec.ForProvider.Rules[i3].Rules[i4]")
+			}
+
+		}
+	}
+	for i3 := 0; i3 < len(mg.Spec.ForProvider.Rules); i3++ {
+		if mg.Spec.ForProvider.Rules[i3].Not != nil {
+			err = mg.resolveRuleReferences(ctx, r, mg.Spec.ForProvider.Rules[i3].Not)
+			if err != nil {
+				return errors.Wrap(err, "mg.Spec.ForProvider.Rules[i3].Not")
+			}
+
+		}
+	}
+
+	return nil
+}
+
+// resolveRuleReferences resolves the references of a Rule of this Model.
+func (mg *Model) resolveRuleReferences(ctx context.Context, r *reference.APIResolver, v *Rule) error {
+	if v == nil {
+		return nil
+	}
+
+	var rsp reference.ResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(v.IPSetID),
+		Extract:      reference.ExternalName(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    v.IPSetIDRef,
+		Selector:     v.IPSetIDSelector,
+		To: reference.To{
+			List:    &IPSetList{},
+			Managed: &IPSet{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "v.IPSetID")
+	}
+	v.IPSetID = reference.ToPtrValue(rsp.ResolvedValue)
+	v.IPSetIDRef = rsp.ResolvedReference
+
+	for i1 := 0; i1 < len(v.Rules); i1++ {
+		err = mg.resolveRuleReferences(ctx, r, &v.Rules[i1])
+		if err != nil {
+			return errors.Wrap(err, "v.Rules[i1]")
+		}
+
+	}
+	if v.Not != nil {
+		err = mg.resolveRuleReferences(ctx, r, v.Not)
+		if err != nil {
+			return errors.Wrap(err, "v.Not")
+		}
+
+	}
+
+	return nil
+}
+`
+	if diff := cmp.Diff(want, fmt.Sprintf("%#v", f)); diff != "" {
+		t.Errorf("NewResolveReferences(): -want, +got\n%s", diff)
+	}
+}
+
+func TestNewResolveReferencesTypeHelpers(t *testing.T) {
+	exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{{
+		Name: "golang.org/fake",
+		Files: map[string]any{
+			"v1alpha1/model.go": `
+package v1alpha1
+
+type SubnetSpec struct {
+	// +crossplane:generate:reference:type=Subnet
+	SubnetID string
+}
+
+type NetworkSpec struct {
+	// +crossplane:generate:reference:type=VPC
+	VPCID *string
+
+	Subnets []SubnetSpec
+
+	Primary *SubnetSpec
+}
+
+type Labels struct {
+	Values map[string]string
+}
+
+type ModelParameters struct {
+	// +crossplane:generate:reference:type=VPC
+	VPCID *string
+
+	Network NetworkSpec
+
+	Subnets map[string]SubnetSpec
+
+	Labels Labels
+}
+
+type ModelSpec struct {
+	ForProvider ModelParameters
+}
+
+type Model struct {
+	Spec ModelSpec
+}
+`,
+		},
+	}})
+	defer exported.Cleanup()
+	exported.Config.Mode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax
+	pkgs, err := packages.Load(exported.Config, fmt.Sprintf("file=%s", exported.File("golang.org/fake", "v1alpha1/model.go")))
+	if err != nil {
+		t.Error(err)
+	}
+	f := jen.NewFilePath("golang.org/fake/v1alpha1")
+	if err := NewResolveReferences(xptypes.NewTraverser(comments.In(pkgs[0])), "mg", "example.org/client", "example.org/reference", WithTypeHelpers())(f, pkgs[0].Types.Scope().Lookup("Model")); err != nil {
+		t.Error(err)
+	}
+
+	want := `package v1alpha1
+
+import (
+	"context"
+	client "example.org/client"
+	reference "example.org/reference"
+	errors "github.com/pkg/errors"
+	"maps"
+	"slices"
+)
+
+// ResolveReferences of this Model.
+func (mg *Model) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	var err error
+
+	err = mg.resolveModelSpecReferences(ctx, r, &mg.Spec)
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec")
+	}
+
+	return nil
+}
+
+// resolveModelSpecReferences resolves the references of a ModelSpec of this Model.
+func (mg *Model) resolveModelSpecReferences(ctx context.Context, r *reference.APIResolver, v *ModelSpec) error {
+	if v == nil {
+		return nil
+	}
+
+	var err error
+
+	err = mg.resolveModelParametersReferences(ctx, r, &v.ForProvider)
+	if err != nil {
+		return errors.Wrap(err, "v.ForProvider")
+	}
+
+	return nil
+}
+
+// resolveModelParametersReferences resolves the references of a ModelParameters of this Model.
+func (mg *Model) resolveModelParametersReferences(ctx context.Context, r *reference.APIResolver, v *ModelParameters) error {
+	if v == nil {
+		return nil
+	}
+
+	var rsp reference.ResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(v.VPCID),
+		Extract:      reference.ExternalName(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    v.VPCIDRef,
+		Selector:     v.VPCIDSelector,
+		To: reference.To{
+			List:    &VPCList{},
+			Managed: &VPC{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "v.VPCID")
+	}
+	v.VPCID = reference.ToPtrValue(rsp.ResolvedValue)
+	v.VPCIDRef = rsp.ResolvedReference
+
+	err = mg.resolveNetworkSpecReferences(ctx, r, &v.Network)
+	if err != nil {
+		return errors.Wrap(err, "v.Network")
+	}
+
+	for _, k1 := range slices.Sorted(maps.Keys(v.Subnets)) {
+		v1 := v.Subnets[k1]
+		err = mg.resolveSubnetSpecReferences(ctx, r, &v1)
+		if err != nil {
+			return errors.Wrap(err, "v.Subnets[k1]")
+		}
+
+		v.Subnets[k1] = v1
+	}
+
+	return nil
+}
+
+// resolveNetworkSpecReferences resolves the references of a NetworkSpec of this Model.
+func (mg *Model) resolveNetworkSpecReferences(ctx context.Context, r *reference.APIResolver, v *NetworkSpec) error {
+	if v == nil {
+		return nil
+	}
+
+	var rsp reference.ResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(v.VPCID),
+		Extract:      reference.ExternalName(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    v.VPCIDRef,
+		Selector:     v.VPCIDSelector,
+		To: reference.To{
+			List:    &VPCList{},
+			Managed: &VPC{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "v.VPCID")
+	}
+	v.VPCID = reference.ToPtrValue(rsp.ResolvedValue)
+	v.VPCIDRef = rsp.ResolvedReference
+
+	for i1 := 0; i1 < len(v.Subnets); i1++ {
+		err = mg.resolveSubnetSpecReferences(ctx, r, &v.Subnets[i1])
+		if err != nil {
+			return errors.Wrap(err, "v.Subnets[i1]")
+		}
+
+	}
+	if v.Primary != nil {
+		err = mg.resolveSubnetSpecReferences(ctx, r, v.Primary)
+		if err != nil {
+			return errors.Wrap(err, "v.Primary")
+		}
+
+	}
+
+	return nil
+}
+
+// resolveSubnetSpecReferences resolves the references of a SubnetSpec of this Model.
+func (mg *Model) resolveSubnetSpecReferences(ctx context.Context, r *reference.APIResolver, v *SubnetSpec) error {
+	if v == nil {
+		return nil
+	}
+
+	var rsp reference.ResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: v.SubnetID,
+		Extract:      reference.ExternalName(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    v.SubnetIDRef,
+		Selector:     v.SubnetIDSelector,
+		To: reference.To{
+			List:    &SubnetList{},
+			Managed: &Subnet{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "v.SubnetID")
+	}
+	v.SubnetID = rsp.ResolvedValue
+	v.SubnetIDRef = rsp.ResolvedReference
+
+	return nil
+}
+`
+	if diff := cmp.Diff(want, fmt.Sprintf("%#v", f)); diff != "" {
+		t.Errorf("NewResolveReferences(): -want, +got\n%s", diff)
+	}
+}
+
+func TestNewResolveReferencesRoots(t *testing.T) {
+	exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{{
+		Name: "golang.org/fake",
+		Files: map[string]any{
+			"v1alpha1/model.go": `
+package v1alpha1
+
+type ModelParameters struct {
+	// +crossplane:generate:reference:type=Subnet
+	SubnetID *string
+}
+
+type ModelInitParameters struct {
+	// +crossplane:generate:reference:type=Subnet
+	SubnetID *string
+}
+
+type ModelObservation struct {
+	// +crossplane:generate:reference:type=Subnet
+	SubnetID *string
+}
+
+type ModelSpec struct {
+	ForProvider ModelParameters
+
+	InitProvider *ModelInitParameters
+}
+
+type ModelStatus struct {
+	AtProvider ModelObservation
+}
+
+type Model struct {
+	Spec ModelSpec
+
+	Status ModelStatus
+}
+`,
+		},
+	}})
+	defer exported.Cleanup()
+	exported.Config.Mode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax
+	pkgs, err := packages.Load(exported.Config, fmt.Sprintf("file=%s", exported.File("golang.org/fake", "v1alpha1/model.go")))
+	if err != nil {
+		t.Error(err)
+	}
+	traverser := xptypes.NewTraverser(comments.In(pkgs[0]))
+	roots := WithRoots("Spec.ForProvider", "Spec.InitProvider")
+
+	t.Run("IgnoreOutsideRoots", func(t *testing.T) {
+		f := jen.NewFilePath("golang.org/fake/v1alpha1")
+		if err := NewResolveReferences(traverser, "mg", "example.org/client", "example.org/reference", roots)(f, pkgs[0].Types.Scope().Lookup("Model")); err != nil {
+			t.Error(err)
+		}
+
+		want := `package v1alpha1
+
+import (
+	"context"
+	client "example.org/client"
+	reference "example.org/reference"
+	errors "github.com/pkg/errors"
+)
+
+// ResolveReferences of this Model.
+func (mg *Model) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	var rsp reference.ResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.SubnetID),
+		Extract:      reference.ExternalName(),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.ForProvider.SubnetIDRef,
+		Selector:     mg.Spec.ForProvider.SubnetIDSelector,
+		To: reference.To{
+			List:    &SubnetList{},
+			Managed: &Subnet{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.SubnetID")
+	}
+	mg.Spec.ForProvider.SubnetID = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.SubnetIDRef = rsp.ResolvedReference
+
+	if mg.Spec.InitProvider != nil {
+		rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+			CurrentValue: reference.FromPtrValue(mg.Spec.InitProvider.SubnetID),
+			Extract:      reference.ExternalName(),
+			Namespace:    mg.GetNamespace(),
+			Reference:    mg.Spec.InitProvider.SubnetIDRef,
+			Selector:     mg.Spec.InitProvider.SubnetIDSelector,
+			To: reference.To{
+				List:    &SubnetList{},
+				Managed: &Subnet{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.InitProvider.SubnetID")
+		}
+		mg.Spec.InitProvider.SubnetID = reference.ToPtrValue(rsp.ResolvedValue)
+		mg.Spec.InitProvider.SubnetIDRef = rsp.ResolvedReference
+
+	}
+
+	return nil
+}
+`
+		if diff := cmp.Diff(want, fmt.Sprintf("%#v", f)); diff != "" {
+			t.Errorf("NewResolveReferences(): -want, +got\n%s", diff)
+		}
+	})
+
+	t.Run("RejectOutsideRoots", func(t *testing.T) {
+		f := jen.NewFilePath("golang.org/fake/v1alpha1")
+		err := NewResolveReferences(traverser, "mg", "example.org/client", "example.org/reference", roots, WithRejectOutsideRoots())(f, pkgs[0].Types.Scope().Lookup("Model"))
+		if want := "cannot resolve references of field Status.AtProvider.SubnetID"; err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("NewResolveReferences(): want error containing %q, got %v", want, err)
+		}
+	})
+
+	t.Run("UnknownRoot", func(t *testing.T) {
+		f := jen.NewFilePath("golang.org/fake/v1alpha1")
+		err := NewResolveReferences(traverser, "mg", "example.org/client", "example.org/reference", WithRoots("Spec.ForProvider", "Spec.InitProviders"))(f, pkgs[0].Types.Scope().Lookup("Model"))
+		if want := "cannot resolve references in Spec.InitProviders"; err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("NewResolveReferences(): want error containing %q, got %v", want, err)
+		}
+	})
+}
+
+func TestNewResolveReferencesMaxDepth(t *testing.T) {
+	exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{{
+		Name: "golang.org/fake",
+		Files: map[string]any{
+			"v1alpha1/model.go": `
+package v1alpha1
+
+type Inner struct {
+	// +crossplane:generate:reference:type=Subnet
+	SubnetID string
+}
+
+type ModelParameters struct {
+	Inner Inner
+}
+
+type ModelSpec struct {
+	ForProvider ModelParameters
+}
+
+type Model struct {
+	Spec ModelSpec
+}
+`,
+		},
+	}})
+	defer exported.Cleanup()
+	exported.Config.Mode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax
+	pkgs, err := packages.Load(exported.Config, fmt.Sprintf("file=%s", exported.File("golang.org/fake", "v1alpha1/model.go")))
+	if err != nil {
+		t.Error(err)
+	}
+	f := jen.NewFilePath("golang.org/fake/v1alpha1")
+	err = NewResolveReferences(xptypes.NewTraverser(comments.In(pkgs[0]), xptypes.WithMaxDepth(2)), "mg", "example.org/client", "example.org/reference")(f, pkgs[0].Types.Scope().Lookup("Model"))
+	if want := "nested more than 2 types deep"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("NewResolveReferences(): want error containing %q, got %v", want, err)
+	}
+}
+
+func TestNewResolveReferencesUnsupportedType(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		typ    string
+		want   string
+	}{
+		"PointerToSlice": {
+			reason: "Pointers to slices can't be converted to and from strings.",
+			typ:    "*[]string",
+		},
+		"SliceOfInts": {
+			reason: "Slices of int64 values can't be converted to and from strings.",
+			typ:    "[]int64",
+		},
+		"PointerToBool": {
+			reason: "Pointers to bool values can't be converted to and from strings.",
+			typ:    "*bool",
+		},
+		"Int32": {
+			reason: "Only 64 bit integers can be converted to and from strings.",
+			typ:    "int32",
+		},
+		"MapWithUnorderedKeys": {
+			reason: "The keys of maps must be ordered so they can be iterated in sorted order.",
+			typ:    "map[bool]string",
+		},
+		"NamedString": {
+			reason: "Named types would need conversions to and from the resolved string.",
+			typ:    "SubnetID",
+			want:   "unsupported type golang.org/fake/v1alpha1.SubnetID: reference fields of named types are not supported, use string instead",
+		},
+		"PointerToNamedString": {
+			reason: "Pointers to named types would need conversions to and from the resolved string.",
+			typ:    "*SubnetID",
+			want:   "unsupported type *golang.org/fake/v1alpha1.SubnetID",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{{
+				Name: "golang.org/fake",
+				Files: map[string]any{
+					"v1alpha1/model.go": fmt.Sprintf(`
+package v1alpha1
+
+type ModelParameters struct {
+	// +crossplane:generate:reference:type=SecurityGroup
+	SecurityGroupID %s
+}
+
+type ModelSpec struct {
+	ForProvider ModelParameters
+}
+
+type Model struct {
+	Spec ModelSpec
+}
+
+type SubnetID string
+`, tc.typ),
+				},
+			}})
+			defer exported.Cleanup()
+			exported.Config.Mode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax
+			pkgs, err := packages.Load(exported.Config, fmt.Sprintf("file=%s", exported.File("golang.org/fake", "v1alpha1/model.go")))
+			if err != nil {
+				t.Error(err)
+			}
+			f := jen.NewFilePath("golang.org/fake/v1alpha1")
+			err = NewResolveReferences(xptypes.NewTraverser(comments.In(pkgs[0])), "mg", "example.org/client", "example.org/reference")(f, pkgs[0].Types.Scope().Lookup("Model"))
+			want := tc.want
+			if want == "" {
+				want = "unsupported type " + tc.typ
+			}
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("\n%s\nNewResolveReferences(): want error containing %q, got %v", tc.reason, want, err)
+			}
+		})
+	}
+}
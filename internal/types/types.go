@@ -19,10 +19,13 @@ package types
 
 import (
 	"go/types"
+	"slices"
+	"strings"
 
 	"github.com/pkg/errors"
 
 	"github.com/crossplane/crossplane-tools/internal/comments"
+	"github.com/crossplane/crossplane-tools/internal/diagnostic"
 )
 
 // NamedProcessorChain runs multiple NamedProcessors in order.
@@ -32,7 +35,7 @@ type NamedProcessorChain []NamedProcessor
 func (tpc NamedProcessorChain) Process(n *types.Named, comment string) error {
 	for i, tp := range tpc {
 		if err := tp.Process(n, comment); err != nil {
-			return errors.Errorf("type processor at index %d failed", i)
+			return errors.Wrapf(err, "type processor at index %d failed", i)
 		}
 	}
 	return nil
@@ -52,7 +55,7 @@ type FieldProcessorChain []FieldProcessor
 func (fpc FieldProcessorChain) Process(n *types.Named, f *types.Var, tag, comment string, parentFields ...string) error {
 	for i, fp := range fpc {
 		if err := fp.Process(n, f, tag, comment, parentFields...); err != nil {
-			return errors.Errorf("field processor at index %d failed", i)
+			return errors.Wrapf(err, "field processor at index %d failed", i)
 		}
 	}
 	return nil
@@ -63,69 +66,202 @@ type FieldProcessor interface {
 	Process(n *types.Named, f *types.Var, tag string, comment string, parentFields ...string) error
 }
 
+// CycleProcessor processes fields whose type is one of the types that contain
+// them, for example the Rules field of a recursive Rule type. The Traverser
+// doesn't descend into such fields, so that it terminates.
+type CycleProcessor interface {
+	Process(n *types.Named, parentFields ...string) error
+}
+
+// CycleProcessorFn is a function that satisfies CycleProcessor.
+type CycleProcessorFn func(n *types.Named, parentFields ...string) error
+
+// Process calls fn.
+func (fn CycleProcessorFn) Process(n *types.Named, parentFields ...string) error {
+	return fn(n, parentFields...)
+}
+
+// NestedProcessor processes fields whose type is a named struct, for example
+// the Subnet field of a Network type. The Traverser doesn't descend into such
+// fields if it's configured with a NestedProcessor.
+type NestedProcessor interface {
+	Process(n *types.Named, parentFields ...string) error
+}
+
+// NestedProcessorFn is a function that satisfies NestedProcessor.
+type NestedProcessorFn func(n *types.Named, parentFields ...string) error
+
+// Process calls fn.
+func (fn NestedProcessorFn) Process(n *types.Named, parentFields ...string) error {
+	return fn(n, parentFields...)
+}
+
 // ProcessorConfig lets you configure what processors will be run in given traversal.
 type ProcessorConfig struct {
 	Named NamedProcessor
 	Field FieldProcessor
+
+	// Cycle processes fields of recursive types. Such fields are skipped if
+	// it is nil.
+	Cycle CycleProcessor
+
+	// Nested processes fields of named struct types instead of descending
+	// into them, if it isn't nil. Fields of recursive types are processed
+	// by Cycle.
+	Nested NestedProcessor
+}
+
+// DefaultMaxDepth is the maximum number of nested types a Traverser descends
+// into by default.
+const DefaultMaxDepth = 32
+
+// A TraverserOption configures a Traverser.
+type TraverserOption func(t *Traverser)
+
+// WithMaxDepth configures the maximum number of nested types a Traverser
+// descends into, including the type it starts from. Traversal fails if types
+// are nested deeper.
+func WithMaxDepth(n int) TraverserOption {
+	return func(t *Traverser) {
+		t.maxDepth = n
+	}
 }
 
 // NewTraverser returns a new Traverser.
-func NewTraverser(c comments.Comments) *Traverser {
-	return &Traverser{
+func NewTraverser(c comments.Comments, o ...TraverserOption) *Traverser {
+	t := &Traverser{
 		comments: c,
+		maxDepth: DefaultMaxDepth,
+	}
+	for _, fn := range o {
+		fn(t)
 	}
+	return t
 }
 
 // Traverser goes through all fields of given type recursively. It runs the field
 // processor for every field and named processor for every type it encounters
-// during its depth-first traversal.
+// during its depth-first traversal. It doesn't descend into fields whose type
+// is one of the types that contain them; see CycleProcessor. It doesn't descend
+// into fields of named struct types either if configured with a
+// NestedProcessor.
 type Traverser struct {
 	comments comments.Comments
+	maxDepth int
 }
 
-// NOTE(muvaf): We return an error but currently there isn't really anything
-// constructing an error. But we keep that for future type and field processors.
-
 // Traverse given type recursively and run given processors.
-func (t *Traverser) Traverse(n *types.Named, cfg *ProcessorConfig, parentFields ...string) error { //nolint:gocognit // This is complex, but pretty easy to read.
+func (t *Traverser) Traverse(n *types.Named, cfg *ProcessorConfig, parentFields ...string) error {
+	return t.traverse(n, cfg, nil, parentFields...)
+}
+
+func (t *Traverser) traverse(n *types.Named, cfg *ProcessorConfig, ancestors []*types.Named, parentFields ...string) error {
+	if len(ancestors) >= t.maxDepth {
+		return errors.Errorf("type %s is nested more than %d types deep", n.Obj().Name(), t.maxDepth)
+	}
 	if err := cfg.Named.Process(n, t.comments.For(n.Obj())); err != nil {
-		return errors.Wrapf(err, "type processors failed to run for type %s", n.Obj().Name())
+		return diagnostic.At(t.comments.Position(n.Obj()), errors.Wrapf(err, "type processors failed to run for type %s", n.Obj().Name()))
 	}
 	st, ok := n.Underlying().(*types.Struct)
 	if !ok {
 		return nil
 	}
+	ancestors = append(slices.Clip(ancestors), n)
 	for i := range st.NumFields() {
 		field := st.Field(i)
 		tag := st.Tag(i)
 		if err := cfg.Field.Process(n, field, tag, t.comments.For(field), parentFields...); err != nil {
-			return errors.Wrapf(err, "field processors failed to run for field %s of type %s", field.Name(), n.Obj().Name())
+			return diagnostic.At(t.comments.Position(field), errors.Wrapf(err, "field processors failed to run for field %s of type %s", field.Name(), n.Obj().Name()))
+		}
+		ft, prefix := traversable(field.Type())
+		if ft == nil {
+			continue
 		}
-		switch ft := field.Type().(type) {
-		case *types.Named:
-			if err := t.Traverse(ft, cfg, append(parentFields, field.Name())...); err != nil {
-				return errors.Wrapf(err, "failed to traverse type of field %s", field.Name())
+		path := append(slices.Clip(parentFields), prefix+field.Name())
+		if slices.ContainsFunc(ancestors, func(a *types.Named) bool { return types.Identical(a, ft) }) {
+			if cfg.Cycle == nil {
+				continue
 			}
-		case *types.Pointer:
-			if elemType, ok := ft.Elem().(*types.Named); ok {
-				if err := t.Traverse(elemType, cfg, append(parentFields, "*"+field.Name())...); err != nil {
-					return errors.Wrapf(err, "failed to traverse type of field %s", field.Name())
-				}
+			if err := cfg.Cycle.Process(ft, path...); err != nil {
+				return diagnostic.At(t.comments.Position(field), errors.Wrapf(err, "cycle processors failed to run for field %s of type %s", field.Name(), n.Obj().Name()))
 			}
-		case *types.Slice:
-			switch elemType := ft.Elem().(type) {
-			case *types.Named:
-				if err := t.Traverse(elemType, cfg, append(parentFields, "[]"+field.Name())...); err != nil {
-					return errors.Wrapf(err, "failed to traverse type of field %s", field.Name())
-				}
-			case *types.Pointer:
-				if elemElemType, ok := elemType.Elem().(*types.Named); ok {
-					if err := t.Traverse(elemElemType, cfg, append(parentFields, "[]"+"*"+field.Name())...); err != nil {
-						return errors.Wrapf(err, "failed to traverse type of field %s", field.Name())
-					}
-				}
+			continue
+		}
+		if _, ok := ft.Underlying().(*types.Struct); ok && cfg.Nested != nil {
+			if err := cfg.Nested.Process(ft, path...); err != nil {
+				return diagnostic.At(t.comments.Position(field), errors.Wrapf(err, "nested processors failed to run for field %s of type %s", field.Name(), n.Obj().Name()))
 			}
+			continue
+		}
+		if err := t.traverse(ft, cfg, ancestors, path...); err != nil {
+			return errors.Wrapf(err, "failed to traverse type of field %s", field.Name())
 		}
 	}
 	return nil
 }
+
+// Field returns the named type of the field at the supplied path in the
+// supplied type, for example Spec.ForProvider, and the path with the prefix of
+// each field that Traverse uses in paths, for example *InitProvider. It returns
+// a nil type if there's no such field, or if its type isn't one a Traverser
+// descends into.
+func Field(n *types.Named, path string) (*types.Named, []string) {
+	fields := make([]string, 0)
+	for name := range strings.SplitSeq(path, ".") {
+		st, ok := n.Underlying().(*types.Struct)
+		if !ok {
+			return nil, nil
+		}
+		var ft *types.Named
+		prefix := ""
+		for i := range st.NumFields() {
+			if st.Field(i).Name() == name {
+				ft, prefix = traversable(st.Field(i).Type())
+				break
+			}
+		}
+		if ft == nil {
+			return nil, nil
+		}
+		fields = append(fields, prefix+name)
+		n = ft
+	}
+	return n, fields
+}
+
+// traversable returns the named type a Traverser descends into from a field of
+// the supplied type, if any, and the prefix of the field in paths. The prefix
+// is * for pointer fields, [] for slice fields, []* for slice of pointer
+// fields, and map[] or map[]* for map fields.
+func traversable(t types.Type) (*types.Named, string) {
+	switch ft := t.(type) {
+	case *types.Named:
+		return ft, ""
+	case *types.Pointer:
+		if et, ok := ft.Elem().(*types.Named); ok {
+			return et, "*"
+		}
+	case *types.Slice:
+		et, prefix := traversable(ft.Elem())
+		if prefix == "" || prefix == "*" {
+			return et, "[]" + prefix
+		}
+	case *types.Map:
+		// Generated code iterates over the keys of maps in sorted order.
+		if !IsOrdered(ft.Key()) {
+			return nil, ""
+		}
+		et, prefix := traversable(ft.Elem())
+		if prefix == "" || prefix == "*" {
+			return et, "map[]" + prefix
+		}
+	}
+	return nil, ""
+}
+
+// IsOrdered returns true if values of the supplied type may be compared using
+// the < operator, and may therefore be sorted.
+func IsOrdered(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsOrdered != 0
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validate finds problems in the comment markers of a package.
+package validate
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/crossplane/crossplane-tools/internal/comments"
+	"github.com/crossplane/crossplane-tools/internal/diagnostic"
+)
+
+// maxTypoDistance is the maximum edit distance between an unknown marker key
+// and a known one for the unknown key to be considered a typo.
+const maxTypoDistance = 2
+
+// A Marker that is known to the validator.
+type Marker struct {
+	// Key of the marker, for example crossplane:generate:methods.
+	Key string
+
+	// Value returns an error if the supplied value of the marker is invalid.
+	// Markers without a Value function accept any non-empty value.
+	Value func(v string) error
+}
+
+// OneOf returns a Value function that accepts only the supplied values.
+func OneOf(valid ...string) func(v string) error {
+	return func(v string) error {
+		for _, ok := range valid {
+			if v == ok {
+				return nil
+			}
+		}
+		return errors.Errorf("value %q must be one of %s", v, strings.Join(valid, ", "))
+	}
+}
+
+// Identifier is a Value function that accepts only Go identifiers.
+func Identifier(v string) error {
+	if !token.IsIdentifier(v) {
+		return errors.Errorf("value %q must be a Go identifier", v)
+	}
+	return nil
+}
+
+// PositiveInteger is a Value function that accepts only integers greater than
+// zero.
+func PositiveInteger(v string) error {
+	if n, err := strconv.Atoi(v); err != nil || n < 1 {
+		return errors.Errorf("value %q must be a positive integer", v)
+	}
+	return nil
+}
+
+// FieldPaths is a Value function that accepts only comma separated paths to
+// fields, for example Spec.ForProvider,Spec.InitProvider.
+func FieldPaths(v string) error {
+	for p := range strings.SplitSeq(v, ",") {
+		for f := range strings.SplitSeq(p, ".") {
+			if !token.IsIdentifier(f) {
+				return errors.Errorf("value %q must be a comma separated list of field paths, such as Spec.ForProvider", v)
+			}
+		}
+	}
+	return nil
+}
+
+// Markers returns an error for each malformed marker in the supplied package.
+// Markers that begin with the supplied prefix must be known, must not be
+// repeated within a comment, and must have a valid value. Markers that don't
+// begin with the supplied prefix are only reported if they look like a typo of
+// a known marker.
+func Markers(p *packages.Package, prefix string, known ...Marker) diagnostic.Errors {
+	byKey := make(map[string]Marker, len(known))
+	for _, m := range known {
+		byKey[m.Key] = m
+	}
+
+	errs := diagnostic.Errors{}
+	for _, f := range p.Syntax {
+		for _, g := range f.Comments {
+			errs = append(errs, group(p.Fset, g, prefix, byKey)...)
+		}
+	}
+	return errs
+}
+
+func group(fset *token.FileSet, g *ast.CommentGroup, prefix string, known map[string]Marker) diagnostic.Errors {
+	errs := diagnostic.Errors{}
+	seen := map[string]bool{}
+	for _, c := range g.List {
+		line, ok := strings.CutPrefix(c.Text, "//")
+		if !ok {
+			continue
+		}
+		line, ok = strings.CutPrefix(strings.TrimSpace(line), comments.DefaultMarkerPrefix)
+		if !ok {
+			continue
+		}
+		key, value, hasValue := strings.Cut(line, "=")
+		pos := fset.Position(c.Slash)
+
+		m, ok := known[key]
+		if !ok {
+			if s := suggest(key, known); s != "" {
+				errs = append(errs, diagnostic.Errorf(pos, "unknown marker %q, did you mean %q?", key, s))
+				continue
+			}
+			if strings.HasPrefix(key, prefix) {
+				errs = append(errs, diagnostic.Errorf(pos, "unknown marker %q", key))
+			}
+			continue
+		}
+
+		if seen[key] {
+			errs = append(errs, diagnostic.Errorf(pos, "duplicate marker %q", key))
+			continue
+		}
+		seen[key] = true
+
+		if !hasValue || strings.TrimSpace(value) == "" {
+			errs = append(errs, diagnostic.Errorf(pos, "marker %q must have a value", key))
+			continue
+		}
+		if m.Value == nil {
+			continue
+		}
+		if err := m.Value(value); err != nil {
+			errs = append(errs, diagnostic.At(pos, errors.Wrapf(err, "invalid marker %q", key)))
+		}
+	}
+	return errs
+}
+
+// suggest returns the known marker key that the supplied unknown key is most
+// likely a typo of, if any.
+func suggest(key string, known map[string]Marker) string {
+	best, distance := "", maxTypoDistance+1
+	for k := range known {
+		if strings.EqualFold(k, key) {
+			return k
+		}
+		if d := levenshtein(k, key); d < distance || (d == distance && k < best) {
+			best, distance = k, d
+		}
+	}
+	if distance > maxTypoDistance {
+		return ""
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between the supplied strings.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
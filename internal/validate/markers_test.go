@@ -0,0 +1,161 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/crossplane/crossplane-tools/internal/diagnostic"
+)
+
+func TestMarkers(t *testing.T) {
+	known := []Marker{
+		{Key: "crossplane:generate:methods", Value: OneOf("true", "false")},
+		{Key: "crossplane:generate:reference:type"},
+		{Key: "crossplane:generate:reference:refFieldName", Value: Identifier},
+		{Key: "crossplane:generate:reference:maxDepth", Value: PositiveInteger},
+		{Key: "crossplane:generate:reference:roots", Value: FieldPaths},
+		{Key: "crossplane:generate:reference:extractor", Value: func(v string) error {
+			if v != "ExternalName()" {
+				return errors.New("not a function call")
+			}
+			return nil
+		}},
+	}
+
+	cases := map[string]struct {
+		src  string
+		want []string
+	}{
+		"Valid": {
+			src: `package v1
+
+// +kubebuilder:object:root=true
+// +crossplane:generate:methods=false
+// +crossplane:generate:reference:maxDepth=8
+// +crossplane:generate:reference:roots=Spec.ForProvider,Spec.InitProvider
+type A struct {
+	// +crossplane:generate:reference:type=B
+	// +crossplane:generate:reference:refFieldName=BRef
+	// +crossplane:generate:reference:extractor=ExternalName()
+	B string
+}
+`,
+			want: []string{},
+		},
+		"Unknown": {
+			src: `package v1
+
+// +crossplane:generate:frobnicate=true
+type A struct{}
+`,
+			want: []string{`f.go:3:1: unknown marker "crossplane:generate:frobnicate"`},
+		},
+		"Typo": {
+			src: `package v1
+
+type A struct {
+	// +crossplane:generate:reference:type=B
+	// +crossplane:generate:reference:refFieldname=BRef
+	B string
+}
+`,
+			want: []string{`f.go:5:2: unknown marker "crossplane:generate:reference:refFieldname", did you mean "crossplane:generate:reference:refFieldName"?`},
+		},
+		"TypoInPrefix": {
+			src: `package v1
+
+//+crossplane:generat:methods=false
+type A struct{}
+`,
+			want: []string{`f.go:3:1: unknown marker "crossplane:generat:methods", did you mean "crossplane:generate:methods"?`},
+		},
+		"Duplicate": {
+			src: `package v1
+
+type A struct {
+	// +crossplane:generate:reference:type=B
+	// +crossplane:generate:reference:type=C
+	B string
+}
+`,
+			want: []string{`f.go:5:2: duplicate marker "crossplane:generate:reference:type"`},
+		},
+		"Empty": {
+			src: `package v1
+
+type A struct {
+	// +crossplane:generate:reference:type=
+	B string
+
+	// +crossplane:generate:reference:type
+	C string
+}
+`,
+			want: []string{
+				`f.go:4:2: marker "crossplane:generate:reference:type" must have a value`,
+				`f.go:7:2: marker "crossplane:generate:reference:type" must have a value`,
+			},
+		},
+		"InvalidValues": {
+			src: `package v1
+
+// +crossplane:generate:methods=nope
+// +crossplane:generate:reference:maxDepth=0
+// +crossplane:generate:reference:roots=Spec.ForProvider,
+type A struct {
+	// +crossplane:generate:reference:type=B
+	// +crossplane:generate:reference:refFieldName=b-ref
+	// +crossplane:generate:reference:extractor=ExternalName
+	B string
+}
+`,
+			want: []string{
+				`f.go:3:1: invalid marker "crossplane:generate:methods": value "nope" must be one of true, false`,
+				`f.go:4:1: invalid marker "crossplane:generate:reference:maxDepth": value "0" must be a positive integer`,
+				`f.go:5:1: invalid marker "crossplane:generate:reference:roots": value "Spec.ForProvider," must be a comma separated list of field paths, such as Spec.ForProvider`,
+				`f.go:8:2: invalid marker "crossplane:generate:reference:refFieldName": value "b-ref" must be a Go identifier`,
+				`f.go:9:2: invalid marker "crossplane:generate:reference:extractor": not a function call`,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, "f.go", tc.src, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &packages.Package{Fset: fset, Syntax: []*ast.File{f}}
+
+			got := []string{}
+			for _, err := range Markers(p, "crossplane:generate:", known...) {
+				got = append(got, diagnostic.Format(err))
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Markers(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,619 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package angryjet generates Crossplane method sets for Go types. It is the
+// library behind the angryjet command, for tools that want to embed it.
+package angryjet
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/crossplane/crossplane-tools/internal/comments"
+	"github.com/crossplane/crossplane-tools/internal/diagnostic"
+	"github.com/crossplane/crossplane-tools/internal/fields"
+	"github.com/crossplane/crossplane-tools/internal/generate"
+	"github.com/crossplane/crossplane-tools/internal/match"
+	"github.com/crossplane/crossplane-tools/internal/method"
+	"github.com/crossplane/crossplane-tools/internal/types"
+	"github.com/crossplane/crossplane-tools/internal/validate"
+)
+
+const (
+	// LoadMode used to load all packages.
+	LoadMode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax
+
+	// DisableMarker used to disable generation of managed resource methods for
+	// a type that otherwise appears to be a managed resource that is missing a
+	// subnet of its methods.
+	DisableMarker = "crossplane:generate:methods"
+)
+
+// Imports used in generated code.
+const (
+	CoreAlias  = "corev1"
+	CoreImport = "k8s.io/api/core/v1"
+
+	MetaAlias  = "metav1"
+	MetaImport = "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ClientAlias  = "client"
+	ClientImport = "sigs.k8s.io/controller-runtime/pkg/client"
+
+	RuntimeAlias  = "xpv1"
+	RuntimeImport = "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+
+	RuntimeV2Alias  = "xpv2"
+	RuntimeV2Import = "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	PackageAlias  = "pkgv1"
+	PackageImport = "github.com/crossplane/crossplane/apis/v2/pkg/v1"
+
+	ResourceAlias  = "resource"
+	ResourceImport = "github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+
+	ReferenceAlias  = "reference"
+	ReferenceImport = "github.com/crossplane/crossplane-runtime/v2/pkg/reference"
+)
+
+// The building blocks of a Generator. Tools may use these to register their
+// own kinds of resource.
+type (
+	// A Generator describes a method set that may be generated for one kind
+	// of resource using one flavor of API types.
+	Generator = generate.Generator
+
+	// A Registry of generators.
+	Registry = generate.Registry
+
+	// A MethodSet is a map of method names to the Methods that produce them.
+	MethodSet = method.Set
+
+	// A Method adds a method on the supplied object in the supplied file.
+	Method = method.New
+
+	// A Matcher determines whether an object matches.
+	Matcher = match.Matcher
+
+	// A MatcherFunc is a function that returns true if the supplied object
+	// matches.
+	MatcherFunc = match.Object
+
+	// Comments of a particular package.
+	Comments = comments.Comments
+
+	// Markers are comments that begin with a special character, typically +.
+	Markers = comments.Markers
+
+	// A Traverser goes through all fields of a type recursively.
+	Traverser = types.Traverser
+
+	// Errors that occurred while generating methods. Each error may have a
+	// position in a Go source file; Errors formats each with its position.
+	Errors = diagnostic.Errors
+)
+
+// NewRegistry returns a registry of the supplied generators. It panics if the
+// generators are invalid; use Register to register generators that may be.
+func NewRegistry(g ...Generator) *Registry {
+	return generate.NewRegistry(g...)
+}
+
+// CommentsIn returns all comments in the supplied package.
+func CommentsIn(p *packages.Package) Comments {
+	return comments.In(p)
+}
+
+// ParseMarkers parses comment markers from the supplied comment.
+func ParseMarkers(comment string) Markers {
+	return comments.ParseMarkers(comment)
+}
+
+// NewTraverser returns a Traverser that reads field comments from the supplied
+// Comments.
+func NewTraverser(c Comments) *Traverser {
+	return types.NewTraverser(c)
+}
+
+// Filename returns the default name of the file for the supplied generator
+// output, for example zz_generated.managed.go for the managed output.
+func Filename(output string) string {
+	return generate.Filename(output)
+}
+
+// Format returns the supplied error or warning prefixed with its position in a
+// Go source file, if it has one.
+func Format(err error) string {
+	return diagnostic.Format(err)
+}
+
+// A File that angryjet would generate.
+type File struct {
+	// Path of the file.
+	Path string
+
+	// Contents of the file.
+	Contents []byte
+
+	// Stale files were previously generated by angryjet, but would no longer
+	// contain any methods. Write deletes stale files.
+	Stale bool
+}
+
+type options struct {
+	header         string
+	generators     *Registry
+	filenames      map[string]string
+	importAliases  map[string]string
+	enabled        map[string]bool
+	disabled       map[string]bool
+	overrides      []override
+	jobs           int
+	markerSeverity Severity
+	warn           func(w error)
+	forks          fields.Forks
+	modules        map[string]string
+	filter         string
+	referenceRoots []string
+}
+
+// newOptions returns the supplied options applied to the defaults.
+func newOptions(o ...Option) (*options, error) {
+	opts := &options{
+		filenames:      map[string]string{},
+		jobs:           1,
+		markerSeverity: SeverityWarning,
+		warn:           func(_ error) {},
+		forks:          fields.Forks{},
+		modules:        map[string]string{},
+	}
+	for _, fn := range o {
+		fn(opts)
+	}
+	return opts, opts.validate()
+}
+
+type override struct {
+	pattern string
+	options []Option
+}
+
+// forPackage returns the options that apply to the supplied package, i.e.
+// these options with any matching package options applied.
+func (o *options) forPackage(pkgPath string) *options {
+	po := *o
+	po.filenames = maps.Clone(o.filenames)
+	po.importAliases = maps.Clone(o.importAliases)
+	po.enabled = maps.Clone(o.enabled)
+	po.disabled = maps.Clone(o.disabled)
+	po.forks = maps.Clone(o.forks)
+	po.modules = maps.Clone(o.modules)
+	for _, ov := range o.overrides {
+		if !matchPattern(ov.pattern, pkgPath) {
+			continue
+		}
+		for _, fn := range ov.options {
+			fn(&po)
+		}
+	}
+	return &po
+}
+
+// path returns the path of the file the supplied output is written to for the
+// supplied package.
+func (o *options) path(p *packages.Package, output string) string {
+	filename, ok := o.filenames[output]
+	if !ok {
+		filename = generate.Filename(output)
+	}
+	return filepath.Join(filepath.Dir(p.GoFiles[0]), filename)
+}
+
+// registry returns the generators to run. Unless others were specified these
+// are the default generators, configured by these options.
+func (o *options) registry() *Registry {
+	if o.generators == nil {
+		return defaultGenerators(o.referenceRoots)
+	}
+	return o.generators
+}
+
+// registered returns the registered generators, with matchers that match the
+// types of the accepted forks and remapped modules.
+func (o *options) registered() []Generator {
+	m := fields.Modules{Forks: o.forks, Remapped: o.modules}
+	gs := o.registry().Generators()
+	for i := range gs {
+		gs[i].Matcher = match.ForModules(gs[i].Matcher, m)
+	}
+	return gs
+}
+
+// runs returns true if the supplied generator should run.
+func (o *options) runs(g Generator) bool {
+	if o.disabled[g.Name] {
+		return false
+	}
+	return len(o.enabled) == 0 || o.enabled[g.Name]
+}
+
+// validate returns an error if the options enable or disable generators that
+// are not registered, or specify an invalid filter.
+func (o *options) validate() error {
+	for _, names := range []map[string]bool{o.enabled, o.disabled} {
+		for _, name := range slices.Sorted(maps.Keys(names)) {
+			if _, ok := o.registry().Get(name); !ok {
+				return errors.Errorf("unknown generator %q", name)
+			}
+		}
+	}
+	_, err := o.include(comments.Comments{}, declarations{})
+	return errors.Wrap(err, "invalid filter")
+}
+
+// include returns a matcher that matches the types the filter restricts
+// generation to, reading comment markers from the supplied comments. Generators
+// match the types they generate methods for, per the supplied declarations and
+// their scope. It matches every type if there is no filter.
+func (o *options) include(c comments.Comments, d declarations) (match.Object, error) {
+	if o.filter == "" {
+		return match.AllOf(), nil
+	}
+	e, err := match.ParseExpression(o.filter)
+	if err != nil {
+		return nil, err
+	}
+	ms := map[string]match.Matcher{}
+	for _, g := range o.registered() {
+		ms[g.Name] = d.Matcher(g, c)
+	}
+	return e.Matcher(match.Environment{Comments: c, Matchers: ms})
+}
+
+// An Option configures generation.
+type Option func(o *options)
+
+// WithHeader specifies a string to be written as a comment to the top of all
+// generated files.
+func WithHeader(h string) Option {
+	return func(o *options) {
+		o.header = h
+	}
+}
+
+// WithGenerators specifies the generators to run. DefaultGenerators are run
+// if none are specified.
+func WithGenerators(r *Registry) Option {
+	return func(o *options) {
+		o.generators = r
+	}
+}
+
+// WithFilename specifies the name of the file that generators with the
+// supplied output write to. Files are named zz_generated.<output>.go by
+// default.
+func WithFilename(output, filename string) Option {
+	return func(o *options) {
+		o.filenames[output] = filename
+	}
+}
+
+// WithImportAliases specifies aliases used by generated code, keyed by import
+// path. They take precedence over the aliases specified by generators.
+func WithImportAliases(ia map[string]string) Option {
+	return func(o *options) {
+		if o.importAliases == nil {
+			o.importAliases = map[string]string{}
+		}
+		maps.Copy(o.importAliases, ia)
+	}
+}
+
+// WithEnabledGenerators specifies the names of the generators to run. All
+// registered generators run by default. Enabling a generator overrides any
+// earlier WithDisabledGenerators.
+func WithEnabledGenerators(names ...string) Option {
+	return func(o *options) {
+		o.enabled = map[string]bool{}
+		for _, n := range names {
+			o.enabled[n] = true
+			delete(o.disabled, n)
+		}
+	}
+}
+
+// WithDisabledGenerators specifies the names of generators not to run.
+func WithDisabledGenerators(names ...string) Option {
+	return func(o *options) {
+		if o.disabled == nil {
+			o.disabled = map[string]bool{}
+		}
+		for _, n := range names {
+			o.disabled[n] = true
+		}
+	}
+}
+
+// WithPackageOptions specifies options that apply only to packages whose
+// import path matches the supplied pattern. As with the go command, '...' in a
+// pattern matches any string. Package options are applied in order, after all
+// other options.
+func WithPackageOptions(pattern string, po ...Option) Option {
+	return func(o *options) {
+		o.overrides = append(o.overrides, override{pattern: pattern, options: po})
+	}
+}
+
+// WithJobs specifies how many packages may be generated concurrently. Packages
+// are generated one at a time by default.
+func WithJobs(n int) Option {
+	return func(o *options) {
+		o.jobs = max(n, 1)
+	}
+}
+
+// WithMarkerSeverity specifies how malformed markers, such as unknown or
+// duplicate crossplane:generate markers, are reported. They are reported as
+// warnings by default.
+func WithMarkerSeverity(s Severity) Option {
+	return func(o *options) {
+		o.markerSeverity = s
+	}
+}
+
+// WithWarnings specifies a function that is called with each warning. Each
+// warning may have a position in a Go source file; use Format to include it.
+// Warnings are discarded by default.
+func WithWarnings(fn func(w error)) Option {
+	return func(o *options) {
+		o.warn = fn
+	}
+}
+
+// WithAcceptedModules specifies modules whose types are accepted in place of
+// those of the supplied module when matching types, for example forks of
+// github.com/crossplane/crossplane-runtime/v2. Types are matched by their
+// package path and name, so without this option the types of a fork imported
+// using its own module path don't match. Accepted modules apply to all
+// packages; they have no effect as package options.
+func WithAcceptedModules(module string, forks ...string) Option {
+	return func(o *options) {
+		o.forks[module] = append(o.forks[module], forks...)
+	}
+}
+
+// WithModulePath specifies a module whose packages are used in place of those
+// of the supplied module, for example a fork of, or a newer major version of,
+// github.com/crossplane/crossplane-runtime/v2. Types are matched against, and
+// generated code imports, the packages of the replacement module. Module paths
+// apply to all packages; they have no effect as package options.
+func WithModulePath(module, replacement string) Option {
+	return func(o *options) {
+		o.modules[module] = replacement
+	}
+}
+
+// WithFilter restricts generation to the types that match the supplied
+// expression. Expressions combine the functions path, name, marker and
+// matches using &&, || and !, for example:
+//
+//	(matches("managed-legacy") || matches("managed-legacy-core")) && !path("/deprecated/")
+//
+// path and name match the import path of a type's package and the type's
+// name against a regular expression, marker matches types with a comment marker
+// written key=value, and matches matches the types that the named generator
+// generates methods for, taking its scope and declared kinds into account. Files that would have methods of types the filter excludes
+// are neither written nor deleted.
+func WithFilter(expr string) Option {
+	return func(o *options) {
+		o.filter = expr
+	}
+}
+
+// WithReferenceRoots specifies the fields of managed resources whose references
+// are resolved, for example Spec.ForProvider and Spec.InitProvider, unless a
+// managed resource specifies its own using the reference:roots marker. Roots a
+// managed resource doesn't have are ignored, so that the same roots may be used
+// for managed resources with and without a Spec.InitProvider. The references of
+// a managed resource that has none of the roots are resolved wherever they're
+// declared. Reference roots configure the default generators; they don't
+// affect generators specified using WithGenerators.
+func WithReferenceRoots(roots ...string) Option {
+	return func(o *options) {
+		o.referenceRoots = roots
+	}
+}
+
+// Generate loads the packages matching the supplied patterns and returns the
+// files that would be generated for them. LoadMode is added to the supplied
+// config's mode.
+func Generate(cfg *packages.Config, patterns []string, o ...Option) ([]File, error) {
+	c := &packages.Config{}
+	if cfg != nil {
+		*c = *cfg
+	}
+	c.Mode |= LoadMode
+
+	pkgs, err := packages.Load(c, patterns...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot load packages %v", patterns)
+	}
+	return GeneratePackages(pkgs, o...)
+}
+
+// GeneratePackages returns the files that would be generated for the supplied
+// packages, which must have been loaded using at least LoadMode. Packages that
+// can't be generated don't stop the others from being generated; the files of
+// the packages that could be generated are returned along with an Errors that
+// describes those that couldn't.
+func GeneratePackages(pkgs []*packages.Package, o ...Option) ([]File, error) {
+	opts, err := newOptions(o...)
+	if err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		files    []File
+		errs     Errors
+		warnings Errors
+	}
+	results := make([]result, len(pkgs))
+	next := make(chan int)
+	wg := &sync.WaitGroup{}
+	for range min(opts.jobs, len(pkgs)) {
+		wg.Go(func() {
+			for i := range next {
+				p := pkgs[i]
+				if len(p.Errors) > 0 {
+					// Load errors are already prefixed with their position.
+					for _, err := range p.Errors {
+						results[i].errs = append(results[i].errs, err)
+					}
+					continue
+				}
+				results[i].files, results[i].errs, results[i].warnings = generatePackage(p, opts)
+			}
+		})
+	}
+	for i := range pkgs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	files := make([]File, 0)
+	errs := Errors{}
+	for _, r := range results {
+		files = append(files, r.files...)
+		errs = errs.Append(r.errs...)
+		for _, w := range r.warnings {
+			opts.warn(w)
+		}
+	}
+	return files, errs.Err()
+}
+
+func generatePackage(p *packages.Package, opts *options) ([]File, Errors, Errors) {
+	if len(p.GoFiles) == 0 {
+		return nil, nil, nil
+	}
+
+	opts = opts.forPackage(p.PkgPath)
+	if err := opts.validate(); err != nil {
+		return nil, Errors{errors.Wrapf(err, "invalid options for package %s", p.PkgPath)}, nil
+	}
+
+	warnings := Errors{}
+	if invalid := validate.Markers(p, MarkerPrefix, markers(opts.registry().Generators())...); len(invalid) > 0 {
+		if opts.markerSeverity == SeverityError {
+			return nil, invalid, nil
+		}
+		warnings = append(warnings, invalid...)
+	}
+
+	c := comments.In(p)
+	gs := opts.registered()
+	d, errs := declare(p, c, gs)
+	include, err := opts.include(c, d)
+	if err != nil {
+		return nil, Errors{errors.Wrapf(err, "invalid filter for package %s", p.PkgPath)}, nil
+	}
+	warnings = append(warnings, nearMisses(p, c, d, include, opts)...)
+
+	// Generators that share an output are rendered to the same file, so that
+	// they don't overwrite each other's methods.
+	outputs := make([]string, 0)
+	sets := map[string][]generate.MatchedSet{}
+	excluded := map[string][]match.Matcher{}
+	aliases := map[string][]generate.WriteOption{}
+	for _, g := range gs {
+		if !opts.runs(g) {
+			continue
+		}
+		if _, ok := sets[g.Output]; !ok {
+			outputs = append(outputs, g.Output)
+		}
+		sets[g.Output] = append(sets[g.Output], generate.MatchedSet{
+			Methods: g.Methods(g.Receiver, c),
+			Matcher: match.AllOf(d.Matcher(g, c), include, match.DoesNotHaveMarker(c, DisableMarker, "false")),
+		})
+		excluded[g.Output] = append(excluded[g.Output], match.AllOf(d.Matcher(g, c), match.Not(include), match.DoesNotHaveMarker(c, DisableMarker, "false")))
+		aliases[g.Output] = append(aliases[g.Output], generate.WithImportAliases(g.ImportAliases))
+	}
+
+	files := make([]File, 0, len(outputs))
+	for _, out := range outputs {
+		// Rendering an output without the types the filter excludes would
+		// delete their methods, so outputs with such types are left as is.
+		if matchesAny(p, match.AnyOf(excluded[out]...)) {
+			continue
+		}
+		path := opts.path(p, out)
+		filename := filepath.Base(path)
+		wo := append(slices.Clone(aliases[out]), generate.WithImportAliases(opts.importAliases), generate.WithHeaders(opts.header), generate.WithModulePaths(opts.modules))
+		b, err := generate.Render(p, path, sets[out], wo...)
+		if err != nil {
+			errs = errs.Append(diagnostic.Wrapf(err, "cannot render %s", filename))
+			continue
+		}
+		if b == nil {
+			stale, err := generate.IsGenerated(path)
+			if err != nil {
+				errs = errs.Append(err)
+				continue
+			}
+			if stale {
+				files = append(files, File{Path: path, Stale: true})
+			}
+			continue
+		}
+		files = append(files, File{Path: path, Contents: b})
+	}
+	return files, errs, warnings
+}
+
+// matchesAny returns true if the supplied matcher matches any type of the
+// supplied package.
+func matchesAny(p *packages.Package, m match.Matcher) bool {
+	for _, n := range p.Types.Scope().Names() {
+		if m.Match(p.Types.Scope().Lookup(n)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Write the supplied files to disk, deleting any that are stale.
+func Write(files []File) error {
+	for _, f := range files {
+		if f.Stale {
+			if err := os.Remove(f.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return errors.Wrapf(err, "cannot delete %s", f.Path)
+			}
+			continue
+		}
+		if err := os.WriteFile(f.Path, f.Contents, 0o644); err != nil { //nolint:gosec // We're comfortable with this being world readable.
+			return errors.Wrapf(err, "cannot write %s", f.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,1934 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package angryjet
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/packages/packagestest"
+)
+
+// The fixture below models one kind for each shape angryjet generates for:
+//
+//   - Legacy/Modern: the crossplane-runtime common/v1 (cluster) and common/v2
+//     (namespaced) types the generators have always supported.
+//   - Core: the same shapes but sourced from the crossplane/apis/v2/core/v2
+//     module that Crossplane v2.3 moved the common types into. These are matched
+//     by the *Core generators.
+const (
+	angryjetFixtureSource = `package v1alpha1
+
+import (
+	xprv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	xprv2 "github.com/crossplane/crossplane-runtime/v2/apis/common/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	pkgv1 "github.com/crossplane/crossplane/apis/v2/pkg/v1"
+)
+
+type ReferenceTargetSpec struct {
+	xpv2.ClusterManagedResourceSpec
+}
+
+type ReferenceTargetStatus struct {
+	xpv2.ManagedResourceStatus
+}
+
+type ReferenceTarget struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   ReferenceTargetSpec
+	Status ReferenceTargetStatus
+}
+
+type ReferenceTargetList struct {
+	metav1.TypeMeta
+	Items []ReferenceTarget
+}
+
+type NamespacedResourceParameters struct {
+	// +crossplane:generate:reference:type=ReferenceTarget
+	Target string
+}
+
+type NamespacedResourceSpec struct {
+	xpv2.ManagedResourceSpec
+	ForProvider NamespacedResourceParameters
+}
+
+type NamespacedResourceStatus struct {
+	xpv2.ManagedResourceStatus
+}
+
+type NamespacedResource struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   NamespacedResourceSpec
+	Status NamespacedResourceStatus
+}
+
+type NamespacedResourceList struct {
+	metav1.TypeMeta
+	Items []NamespacedResource
+}
+
+type ClusterResourceParameters struct {
+	// +crossplane:generate:reference:type=ReferenceTarget
+	Target string
+}
+
+type ClusterResourceSpec struct {
+	xpv2.ClusterManagedResourceSpec
+	ForProvider ClusterResourceParameters
+}
+
+type ClusterResourceStatus struct {
+	xpv2.ManagedResourceStatus
+}
+
+type ClusterResource struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   ClusterResourceSpec
+	Status ClusterResourceStatus
+}
+
+type ClusterResourceList struct {
+	metav1.TypeMeta
+	Items []ClusterResource
+}
+
+type ModernResourceParameters struct {
+	// +crossplane:generate:reference:type=ReferenceTarget
+	Target string
+}
+
+type ModernResourceSpec struct {
+	xprv2.ManagedResourceSpec
+	ForProvider ModernResourceParameters
+}
+
+type ModernResourceStatus struct {
+	xprv1.ResourceStatus
+}
+
+type ModernResource struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   ModernResourceSpec
+	Status ModernResourceStatus
+}
+
+type ModernResourceList struct {
+	metav1.TypeMeta
+	Items []ModernResource
+}
+
+// A legacy (cluster-scoped) managed resource using crossplane-runtime common/v1
+// types, exercising the managed-legacy, managedlist-legacy, and non-namespaced
+// references-legacy generators.
+type LegacyResourceParameters struct {
+	// +crossplane:generate:reference:type=ReferenceTarget
+	Target string
+}
+
+type LegacyResourceSpec struct {
+	xprv1.ResourceSpec
+	ForProvider LegacyResourceParameters
+}
+
+type LegacyResourceStatus struct {
+	xprv1.ResourceStatus
+}
+
+type LegacyResource struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   LegacyResourceSpec
+	Status LegacyResourceStatus
+}
+
+type LegacyResourceList struct {
+	metav1.TypeMeta
+	Items []LegacyResource
+}
+
+// A provider config usage embedding the crossplane-runtime common/v1 non-typed
+// usage, exercising the providerconfigusage-legacy and
+// providerconfigusagelist-legacy generators.
+type RuntimeLegacyProviderConfigUsage struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	xprv1.ProviderConfigUsage
+}
+
+type RuntimeLegacyProviderConfigUsageList struct {
+	metav1.TypeMeta
+	Items []RuntimeLegacyProviderConfigUsage
+}
+
+// A provider config usage embedding the crossplane-runtime common/v2 typed
+// usage, exercising the common/v2 providerconfigusage-modern generator.
+type RuntimeModernProviderConfigUsage struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	xprv2.TypedProviderConfigUsage
+}
+
+type RuntimeModernProviderConfigUsageList struct {
+	metav1.TypeMeta
+	Items []RuntimeModernProviderConfigUsage
+}
+
+// A provider config usage embedding the core API v2 typed usage, exercising
+// the providerconfigusage-modern-core generator.
+type CoreModernProviderConfigUsage struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	xpv2.TypedProviderConfigUsage
+}
+
+type CoreModernProviderConfigUsageList struct {
+	metav1.TypeMeta
+	Items []CoreModernProviderConfigUsage
+}
+
+// A provider config usage embedding the core API v2 non-typed usage, exercising
+// the providerconfigusage-legacy-core generator.
+type CoreLegacyProviderConfigUsage struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	xpv2.ProviderConfigUsage
+}
+
+type CoreLegacyProviderConfigUsageList struct {
+	metav1.TypeMeta
+	Items []CoreLegacyProviderConfigUsage
+}
+
+type CoreProviderCredentials struct {
+	Source xpv2.CredentialsSource
+	xpv2.CommonCredentialSelectors
+}
+
+type CoreProviderConfigSpec struct {
+	xprv1.ProviderConfigSpec
+	Credentials CoreProviderCredentials
+}
+
+// A CoreProviderConfigStatus reflects the observed state of a ProviderConfig.
+type CoreProviderConfigStatus struct {
+	xpv2.ProviderConfigStatus
+}
+
+type CoreProviderConfig struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   CoreProviderConfigSpec
+	Status CoreProviderConfigStatus
+}
+
+// A ClusterCoreProviderConfig is the cluster scoped counterpart of a
+// CoreProviderConfig.
+// +crossplane:generate:referenceConversion=true
+type ClusterCoreProviderConfig struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   CoreProviderConfigSpec
+	Status CoreProviderConfigStatus
+}
+
+// A SharedProviderConfig is cluster scoped per its kubebuilder marker.
+// +kubebuilder:resource:categories={crossplane,provider},scope=Cluster
+type SharedProviderConfig struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   CoreProviderConfigSpec
+	Status CoreProviderConfigStatus
+}
+
+type LegacyProviderConfigSpec struct {
+	xprv1.ProviderConfigSpec
+}
+
+type LegacyProviderConfigStatus struct {
+	xprv1.ProviderConfigStatus
+}
+
+type LegacyProviderConfig struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   LegacyProviderConfigSpec
+	Status LegacyProviderConfigStatus
+}
+
+type XBucketSpec struct {
+	xpv2.CompositeResourceSpec
+}
+
+type XBucketStatus struct {
+	xpv2.CompositeResourceStatus
+}
+
+// An XBucket is a composite resource, exercising the composite-core
+// generator.
+type XBucket struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   XBucketSpec
+	Status XBucketStatus
+}
+
+type BucketSpec struct {
+	xpv2.CompositeClaimSpec
+}
+
+type BucketStatus struct {
+	xpv2.CompositeClaimStatus
+}
+
+type FunctionSpec struct {
+	pkgv1.PackageSpec
+}
+
+type FunctionStatus struct {
+	xpv2.ConditionedStatus
+	pkgv1.PackageStatus
+}
+
+// A Function is a package, exercising the package-core generator.
+type Function struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   FunctionSpec
+	Status FunctionStatus
+}
+
+type FunctionRevisionSpec struct {
+	pkgv1.PackageRevisionSpec
+}
+
+type FunctionRevisionStatus struct {
+	pkgv1.PackageRevisionStatus
+}
+
+// A FunctionRevision is a package revision, exercising the
+// packagerevision-core generator.
+type FunctionRevision struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   FunctionRevisionSpec
+	Status FunctionRevisionStatus
+}
+
+// A Bucket is a composite resource claim, exercising the claim-core
+// generator.
+type Bucket struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   BucketSpec
+	Status BucketStatus
+}
+`
+
+	pkgV1FixtureSource = `package v1
+
+import (
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+)
+
+type PackageSpec struct {
+	Package                     string
+	RevisionActivationPolicy    any
+	RevisionHistoryLimit        any
+	PackagePullSecrets          any
+	PackagePullPolicy           any
+	IgnoreCrossplaneConstraints any
+	SkipDependencyResolution    any
+	CommonLabels                any
+}
+
+type PackageStatus struct {
+	CurrentRevision   string
+	CurrentIdentifier string
+}
+
+type PackageRevisionSpec struct {
+	DesiredState                any
+	Package                     string
+	PackagePullSecrets          any
+	PackagePullPolicy           any
+	Revision                    int64
+	IgnoreCrossplaneConstraints any
+	SkipDependencyResolution    any
+	CommonLabels                any
+}
+
+type PackageRevisionStatus struct {
+	xpv2.ConditionedStatus
+	FoundDependencies     int64
+	InstalledDependencies int64
+	InvalidDependencies   int64
+	ObjectRefs            any
+}
+`
+
+	metaV1FixtureSource = `package v1
+
+type TypeMeta struct{}
+
+type ObjectMeta struct{}
+
+type ListMeta struct{}
+`
+
+	coreV2FixtureSource = `package v2
+
+type ManagedResourceSpec struct {
+	WriteConnectionSecretToReference any
+	PublishConnectionDetailsTo       any
+	ProviderConfigReference          any
+	ManagementPolicies               any
+	DeletionPolicy                   any
+}
+
+type ClusterManagedResourceSpec struct {
+	WriteConnectionSecretToReference any
+	PublishConnectionDetailsTo       any
+	ProviderConfigReference          any
+	ManagementPolicies               any
+	DeletionPolicy                   any
+}
+
+type ManagedResourceStatus struct {
+	ConditionedStatus
+}
+
+type CompositeResourceSpec struct {
+	CompositionSelector              any
+	CompositionReference             any
+	CompositionRevisionReference     any
+	CompositionUpdatePolicy          any
+	ResourceReferences               any
+	ClaimReference                   any
+	WriteConnectionSecretToReference any
+}
+
+type CompositeResourceStatus struct {
+	ConditionedStatus
+	ConnectionDetails ConnectionDetails
+}
+
+type CompositeClaimSpec struct {
+	CompositionSelector              any
+	CompositionReference             any
+	CompositionRevisionReference     any
+	CompositionUpdatePolicy          any
+	CompositeDeletePolicy            any
+	ResourceReference                any
+	WriteConnectionSecretToReference any
+}
+
+type CompositeClaimStatus struct {
+	ConditionedStatus
+	ConnectionDetails ConnectionDetails
+}
+
+type ConnectionDetails struct {
+	LastPublishedTime any
+}
+
+type Condition struct{}
+
+type ConditionType string
+
+type ConditionedStatus struct{}
+
+func (s *ConditionedStatus) SetConditions(c ...Condition) {}
+
+func (s *ConditionedStatus) GetCondition(ct ConditionType) Condition { return Condition{} }
+`
+
+	runtimeV1FixtureSource = `package v1
+
+type ResourceSpec struct {
+	WriteConnectionSecretToReference any
+	PublishConnectionDetailsTo       any
+	ProviderConfigReference          any
+	ManagementPolicies               any
+	DeletionPolicy                   any
+}
+
+type ResourceStatus struct {
+	ConditionedStatus
+}
+
+type ConditionedStatus struct{}
+
+func (s *ConditionedStatus) SetConditions(c ...Condition) {}
+
+func (s *ConditionedStatus) GetCondition(ct ConditionType) Condition { return Condition{} }
+
+type Condition struct{}
+
+type ConditionType string
+
+type User struct{}
+
+type ProviderConfigSpec struct{}
+
+type ProviderConfigUsage struct {
+	ProviderConfigReference any
+	ResourceReference       any
+}
+
+type ProviderConfigStatus struct {
+	Users int64
+}
+
+func (p *ProviderConfigStatus) SetConditions(c ...Condition) {}
+
+func (p *ProviderConfigStatus) GetCondition(ct ConditionType) Condition { return Condition{} }
+
+func (p *ProviderConfigStatus) SetUsers(u ...User) {}
+
+func (p *ProviderConfigStatus) GetUsers() []User { return nil }
+`
+
+	runtimeV2FixtureSource = `package v2
+
+type ManagedResourceSpec struct {
+	WriteConnectionSecretToReference any
+	PublishConnectionDetailsTo       any
+	ProviderConfigReference          any
+	ManagementPolicies               any
+	DeletionPolicy                   any
+}
+
+type TypedProviderConfigUsage struct {
+	ProviderConfigReference any
+	ResourceReference       any
+}
+`
+
+	coreV2ProviderConfigFixtureSource = `package v2
+
+type ProviderConfigStatus struct {
+	ConditionedStatus
+	Users int64
+}
+
+type ProviderConfigReference struct {
+	Name string
+	Kind string
+}
+
+type Reference struct{}
+
+type TypedReference struct{}
+
+type ProviderConfigUsage struct {
+	ProviderConfigReference any
+	ResourceReference       any
+}
+
+type TypedProviderConfigUsage struct {
+	ProviderConfigReference any
+	ResourceReference       any
+}
+
+type CredentialsSource string
+
+type CommonCredentialSelectors struct{}
+`
+)
+
+func TestGenerateMethods(t *testing.T) {
+	type args struct {
+		generator string
+		filename  string
+	}
+
+	type want struct {
+		contains    []string
+		notContains []string
+	}
+
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Managed modern core": {
+			args: args{
+				generator: "managed-modern-core",
+				filename:  "zz_generated.modern_core.go",
+			},
+			want: want{
+				contains: []string{
+					`import xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"`,
+					`func (mg *NamespacedResource) SetProviderConfigReference(r *xpv2.ProviderConfigReference) {`,
+					`func (mg *NamespacedResource) GetWriteConnectionSecretToReference() *xpv2.LocalSecretReference {`,
+					`func (mg *NamespacedResource) GetManagementPolicies() xpv2.ManagementPolicies {`,
+				},
+				notContains: []string{
+					`func (mg *ClusterResource)`,
+					`SetDeletionPolicy`,
+				},
+			},
+		},
+		"Managed legacy core": {
+			args: args{
+				generator: "managed-legacy-core",
+				filename:  "zz_generated.legacy_core.go",
+			},
+			want: want{
+				contains: []string{
+					`import xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"`,
+					`func (mg *ClusterResource) SetProviderConfigReference(r *xpv2.Reference) {`,
+					`func (mg *ClusterResource) GetWriteConnectionSecretToReference() *xpv2.SecretReference {`,
+					`func (mg *ClusterResource) SetDeletionPolicy(r xpv2.DeletionPolicy) {`,
+				},
+				notContains: []string{
+					`func (mg *NamespacedResource)`,
+				},
+			},
+		},
+		"Managed list modern core": {
+			args: args{
+				generator: "managedlist-modern-core",
+				filename:  "zz_generated.modern_core_list.go",
+			},
+			want: want{
+				contains: []string{
+					`import resource "github.com/crossplane/crossplane-runtime/v2/pkg/resource"`,
+					`func (l *NamespacedResourceList) GetItems() []resource.Managed {`,
+				},
+				notContains: []string{
+					`func (l *ClusterResourceList)`,
+				},
+			},
+		},
+		"Managed list legacy core": {
+			args: args{
+				generator: "managedlist-legacy-core",
+				filename:  "zz_generated.legacy_core_list.go",
+			},
+			want: want{
+				contains: []string{
+					`import resource "github.com/crossplane/crossplane-runtime/v2/pkg/resource"`,
+					`func (l *ClusterResourceList) GetItems() []resource.Managed {`,
+				},
+				notContains: []string{
+					`func (l *NamespacedResourceList)`,
+				},
+			},
+		},
+		"References modern core": {
+			args: args{
+				generator: "references-modern-core",
+				filename:  "zz_generated.modern_core_refs.go",
+			},
+			want: want{
+				contains: []string{
+					`reference.NewAPINamespacedResolver(c, mg)`,
+					`var rsp reference.NamespacedResolutionResponse`,
+					`reference.NamespacedResolutionRequest{`,
+					`Namespace:    mg.GetNamespace(),`,
+					`func (mg *NamespacedResource) ResolveReferences(ctx context.Context, c client.Reader) error {`,
+				},
+			},
+		},
+		"References legacy core": {
+			args: args{
+				generator: "references-legacy-core",
+				filename:  "zz_generated.legacy_core_refs.go",
+			},
+			want: want{
+				contains: []string{
+					`reference.NewAPIResolver(c, mg)`,
+					`var rsp reference.ResolutionResponse`,
+					`reference.ResolutionRequest{`,
+					`Namespace:    mg.GetNamespace(),`,
+					`func (mg *ClusterResource) ResolveReferences(ctx context.Context, c client.Reader) error {`,
+				},
+			},
+		},
+		"Managed modern": {
+			args: args{
+				generator: "managed-modern",
+				filename:  "zz_generated.modern.go",
+			},
+			want: want{
+				contains: []string{
+					`import xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"`,
+					`func (mg *ModernResource) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {`,
+					`func (mg *ModernResource) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {`,
+				},
+				notContains: []string{
+					`func (mg *NamespacedResource)`,
+					`SetDeletionPolicy`,
+				},
+			},
+		},
+		"Managed legacy": {
+			args: args{
+				generator: "managed-legacy",
+				filename:  "zz_generated.legacy.go",
+			},
+			want: want{
+				contains: []string{
+					`import xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"`,
+					`func (mg *LegacyResource) SetProviderConfigReference(r *xpv1.Reference) {`,
+					`func (mg *LegacyResource) GetWriteConnectionSecretToReference() *xpv1.SecretReference {`,
+					`func (mg *LegacyResource) SetDeletionPolicy(r xpv1.DeletionPolicy) {`,
+				},
+				notContains: []string{
+					`func (mg *ModernResource)`,
+					`func (mg *ClusterResource)`,
+				},
+			},
+		},
+		"Managed list modern": {
+			args: args{
+				generator: "managedlist-modern",
+				filename:  "zz_generated.modern_list.go",
+			},
+			want: want{
+				contains: []string{
+					`func (l *ModernResourceList) GetItems() []resource.Managed {`,
+				},
+				notContains: []string{
+					`func (l *NamespacedResourceList)`,
+				},
+			},
+		},
+		"Managed list legacy": {
+			args: args{
+				generator: "managedlist-legacy",
+				filename:  "zz_generated.legacy_list.go",
+			},
+			want: want{
+				contains: []string{
+					`import resource "github.com/crossplane/crossplane-runtime/v2/pkg/resource"`,
+					`func (l *LegacyResourceList) GetItems() []resource.Managed {`,
+				},
+				notContains: []string{
+					`func (l *ModernResourceList)`,
+				},
+			},
+		},
+		"Provider config usage modern": {
+			args: args{
+				generator: "providerconfigusage-modern",
+				filename:  "zz_generated.modern_pcu.go",
+			},
+			want: want{
+				contains: []string{
+					`func (p *RuntimeModernProviderConfigUsage) SetProviderConfigReference(r xpv1.ProviderConfigReference) {`,
+					`func (p *RuntimeModernProviderConfigUsage) GetProviderConfigReference() xpv1.ProviderConfigReference {`,
+					`func (p *RuntimeModernProviderConfigUsage) SetResourceReference(r xpv1.TypedReference) {`,
+					`func (p *RuntimeModernProviderConfigUsage) GetResourceReference() xpv1.TypedReference {`,
+				},
+				notContains: []string{
+					`func (p *RuntimeModernProviderConfigUsage) SetProviderConfigReference(r xpv2.ProviderConfigReference) {`,
+					`func (p *CoreModernProviderConfigUsage)`,
+				},
+			},
+		},
+		"Provider config usage legacy": {
+			args: args{
+				generator: "providerconfigusage-legacy",
+				filename:  "zz_generated.legacy_pcu.go",
+			},
+			want: want{
+				contains: []string{
+					`func (p *RuntimeLegacyProviderConfigUsage) SetProviderConfigReference(r xpv1.Reference) {`,
+					`func (p *RuntimeLegacyProviderConfigUsage) GetProviderConfigReference() xpv1.Reference {`,
+					`func (p *RuntimeLegacyProviderConfigUsage) SetResourceReference(r xpv1.TypedReference) {`,
+					`func (p *RuntimeLegacyProviderConfigUsage) GetResourceReference() xpv1.TypedReference {`,
+				},
+				notContains: []string{
+					`func (p *RuntimeLegacyProviderConfigUsage) SetProviderConfigReference(r xpv1.ProviderConfigReference) {`,
+					`func (p *CoreLegacyProviderConfigUsage)`,
+				},
+			},
+		},
+		"Provider config usage modern core": {
+			args: args{
+				generator: "providerconfigusage-modern-core",
+				filename:  "zz_generated.modern_core_pcu.go",
+			},
+			want: want{
+				contains: []string{
+					`func (p *CoreModernProviderConfigUsage) SetProviderConfigReference(r xpv2.ProviderConfigReference) {`,
+					`func (p *CoreModernProviderConfigUsage) GetProviderConfigReference() xpv2.ProviderConfigReference {`,
+					`func (p *CoreModernProviderConfigUsage) SetResourceReference(r xpv2.TypedReference) {`,
+					`func (p *CoreModernProviderConfigUsage) GetResourceReference() xpv2.TypedReference {`,
+				},
+				notContains: []string{
+					`func (p *CoreModernProviderConfigUsage) SetProviderConfigReference(r xpv1.ProviderConfigReference) {`,
+					`func (p *RuntimeModernProviderConfigUsage)`,
+				},
+			},
+		},
+		"Provider config usage legacy core": {
+			args: args{
+				generator: "providerconfigusage-legacy-core",
+				filename:  "zz_generated.legacy_core_pcu.go",
+			},
+			want: want{
+				contains: []string{
+					`func (p *CoreLegacyProviderConfigUsage) SetProviderConfigReference(r xpv2.Reference) {`,
+					`func (p *CoreLegacyProviderConfigUsage) GetProviderConfigReference() xpv2.Reference {`,
+					`func (p *CoreLegacyProviderConfigUsage) SetResourceReference(r xpv2.TypedReference) {`,
+					`func (p *CoreLegacyProviderConfigUsage) GetResourceReference() xpv2.TypedReference {`,
+				},
+				notContains: []string{
+					`func (p *CoreLegacyProviderConfigUsage) SetProviderConfigReference(r xpv2.ProviderConfigReference) {`,
+					`func (p *CoreModernProviderConfigUsage)`,
+				},
+			},
+		},
+		"Provider config usage list modern": {
+			args: args{
+				generator: "providerconfigusagelist-modern",
+				filename:  "zz_generated.modern_pcu_list.go",
+			},
+			want: want{
+				contains: []string{
+					`func (p *RuntimeModernProviderConfigUsageList) GetItems() []resource.ProviderConfigUsage {`,
+				},
+				notContains: []string{
+					`func (p *CoreModernProviderConfigUsageList)`,
+					`func (p *RuntimeLegacyProviderConfigUsageList)`,
+				},
+			},
+		},
+		"Provider config usage list legacy": {
+			args: args{
+				generator: "providerconfigusagelist-legacy",
+				filename:  "zz_generated.legacy_pcu_list.go",
+			},
+			want: want{
+				contains: []string{
+					`func (p *RuntimeLegacyProviderConfigUsageList) GetItems() []resource.ProviderConfigUsage {`,
+				},
+				notContains: []string{
+					`func (p *CoreLegacyProviderConfigUsageList)`,
+					`func (p *RuntimeModernProviderConfigUsageList)`,
+				},
+			},
+		},
+		"Provider config usage list modern core": {
+			args: args{
+				generator: "providerconfigusagelist-modern-core",
+				filename:  "zz_generated.modern_core_pcu_list.go",
+			},
+			want: want{
+				contains: []string{
+					`func (p *CoreModernProviderConfigUsageList) GetItems() []resource.ProviderConfigUsage {`,
+				},
+				notContains: []string{
+					`func (p *CoreLegacyProviderConfigUsageList)`,
+				},
+			},
+		},
+		"Provider config usage list legacy core": {
+			args: args{
+				generator: "providerconfigusagelist-legacy-core",
+				filename:  "zz_generated.legacy_core_pcu_list.go",
+			},
+			want: want{
+				contains: []string{
+					`func (p *CoreLegacyProviderConfigUsageList) GetItems() []resource.ProviderConfigUsage {`,
+				},
+				notContains: []string{
+					`func (p *CoreModernProviderConfigUsageList)`,
+				},
+			},
+		},
+		"Provider config": {
+			args: args{
+				generator: "providerconfig-legacy",
+				filename:  "zz_generated.pc.go",
+			},
+			want: want{
+				contains: []string{
+					`func (p *LegacyProviderConfig) SetConditions(c ...xpv1.Condition) {`,
+					`func (p *LegacyProviderConfig) GetCondition(ct xpv1.ConditionType) xpv1.Condition {`,
+				},
+				notContains: []string{
+					`func (p *CoreProviderConfig)`,
+				},
+			},
+		},
+		"Provider config core": {
+			args: args{
+				generator: "providerconfig-core",
+				filename:  "zz_generated.core_pc.go",
+			},
+			want: want{
+				contains: []string{
+					`func (p *CoreProviderConfig) SetUsers(i int64) {`,
+					`func (p *CoreProviderConfig) GetUsers() int64 {`,
+					`func (p *CoreProviderConfig) SetConditions(c ...xpv2.Condition) {`,
+					`func (p *CoreProviderConfig) GetCondition(ct xpv2.ConditionType) xpv2.Condition {`,
+				},
+				notContains: []string{
+					`func (p *CoreProviderConfig) GetCondition(ct xpv1.ConditionType) xpv1.Condition {`,
+					`func (p *LegacyProviderConfig)`,
+					`func (p *ClusterCoreProviderConfig)`,
+					`func (p *SharedProviderConfig)`,
+				},
+			},
+		},
+		"Cluster provider config core": {
+			args: args{
+				generator: "clusterproviderconfig-core",
+				filename:  "zz_generated.pc.go",
+			},
+			want: want{
+				contains: []string{
+					`func (p *ClusterCoreProviderConfig) SetUsers(i int64) {`,
+					`func (p *SharedProviderConfig) GetCondition(ct xpv2.ConditionType) xpv2.Condition {`,
+					"func ToClusterCoreProviderConfigReference(r *xpv2.ProviderConfigReference) *xpv2.ProviderConfigReference {\n\tif r == nil || r.Kind != \"CoreProviderConfig\" {",
+					"func ToCoreProviderConfigReference(r *xpv2.ProviderConfigReference) *xpv2.ProviderConfigReference {\n\tif r == nil || r.Kind != \"ClusterCoreProviderConfig\" {",
+				},
+				notContains: []string{
+					`func (p *CoreProviderConfig)`,
+					`func ToSharedProviderConfigReference(`,
+				},
+			},
+		},
+		"Composite core": {
+			args: args{
+				generator: "composite-core",
+				filename:  "zz_generated.composite.go",
+			},
+			want: want{
+				contains: []string{
+					`func (xr *XBucket) SetCompositionReference(r *corev1.ObjectReference) {`,
+					`func (xr *XBucket) GetResourceReferences() []corev1.ObjectReference {`,
+					`func (xr *XBucket) SetClaimReference(r *reference.Claim) {`,
+					`func (xr *XBucket) GetWriteConnectionSecretToReference() *xpv2.SecretReference {`,
+					"func (xr *XBucket) GetConnectionDetailsLastPublishedTime() *metav1.Time {\n\treturn xr.Status.ConnectionDetails.LastPublishedTime\n}",
+				},
+				notContains: []string{
+					`func (xr *Bucket)`,
+					`func (xr *XBucket) SetCompositeDeletePolicy(`,
+				},
+			},
+		},
+		"Claim core": {
+			args: args{
+				generator: "claim-core",
+				filename:  "zz_generated.composite.go",
+			},
+			want: want{
+				contains: []string{
+					`func (cm *Bucket) SetCompositeDeletePolicy(r *xpv2.CompositeDeletePolicy) {`,
+					`func (cm *Bucket) GetResourceReference() *corev1.ObjectReference {`,
+					`func (cm *Bucket) GetWriteConnectionSecretToReference() *xpv2.LocalSecretReference {`,
+				},
+				notContains: []string{
+					`func (cm *XBucket)`,
+					`func (cm *Bucket) SetClaimReference(`,
+				},
+			},
+		},
+		"Package core": {
+			args: args{
+				generator: "package-core",
+				filename:  "zz_generated.package.go",
+			},
+			want: want{
+				contains: []string{
+					"func (p *Function) GetSource() string {\n\treturn p.Spec.Package\n}",
+					`func (p *Function) SetActivationPolicy(r *pkgv1.RevisionActivationPolicy) {`,
+					`func (p *Function) GetPackagePullSecrets() []corev1.LocalObjectReference {`,
+					`func (p *Function) SetCommonLabels(r map[string]string) {`,
+					"func (p *Function) GetCurrentRevision() string {\n\treturn p.Status.CurrentRevision\n}",
+					`func (p *Function) SetConditions(c ...xpv2.Condition) {`,
+				},
+				notContains: []string{
+					`func (p *FunctionRevision)`,
+				},
+			},
+		},
+		"Package revision core": {
+			args: args{
+				generator: "packagerevision-core",
+				filename:  "zz_generated.package.go",
+			},
+			want: want{
+				contains: []string{
+					`func (pr *FunctionRevision) SetDesiredState(r pkgv1.PackageRevisionDesiredState) {`,
+					"func (pr *FunctionRevision) GetRevision() int64 {\n\treturn pr.Spec.Revision\n}",
+					"func (pr *FunctionRevision) GetObjects() []xpv2.TypedReference {\n\treturn pr.Status.ObjectRefs\n}",
+					"func (pr *FunctionRevision) GetDependencyStatus() (found, installed, invalid int64) {\n\treturn pr.Status.FoundDependencies, pr.Status.InstalledDependencies, pr.Status.InvalidDependencies\n}",
+				},
+				notContains: []string{
+					`func (pr *Function)`,
+					`func (pr *FunctionRevision) GetActivationPolicy(`,
+				},
+			},
+		},
+		"References modern": {
+			args: args{
+				generator: "references-modern",
+				filename:  "zz_generated.modern_refs.go",
+			},
+			want: want{
+				contains: []string{
+					`reference.NewAPINamespacedResolver(c, mg)`,
+					`var rsp reference.NamespacedResolutionResponse`,
+					`func (mg *ModernResource) ResolveReferences(ctx context.Context, c client.Reader) error {`,
+				},
+			},
+		},
+		"References legacy": {
+			args: args{
+				generator: "references-legacy",
+				filename:  "zz_generated.legacy_refs.go",
+			},
+			want: want{
+				contains: []string{
+					`reference.NewAPIResolver(c, mg)`,
+					`var rsp reference.ResolutionResponse`,
+					`reference.ResolutionRequest{`,
+					`func (mg *LegacyResource) ResolveReferences(ctx context.Context, c client.Reader) error {`,
+				},
+				notContains: []string{
+					`reference.NewAPINamespacedResolver(c, mg)`,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pkg := loadFixturePackage(t)
+			got := generateOutput(t, pkg, tc.args.filename, tc.args.generator)
+
+			for _, want := range tc.want.contains {
+				if !strings.Contains(got, want) {
+					t.Fatalf("generated output missing snippet %q\n%s", want, got)
+				}
+			}
+
+			for _, unwanted := range tc.want.notContains {
+				if strings.Contains(got, unwanted) {
+					t.Fatalf("generated output unexpectedly contained snippet %q\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func loadFixturePackage(t *testing.T) *packages.Package {
+	t.Helper()
+	return loadFixturePackageWith(t, nil)
+}
+
+// loadFixturePackageWith loads the fixture package with the supplied extra
+// files, keyed by their name within the package.
+func loadFixturePackageWith(t *testing.T, extra map[string]string) *packages.Package {
+	t.Helper()
+
+	files := map[string]any{
+		"v1alpha1/model.go": angryjetFixtureSource,
+	}
+	for name, src := range extra {
+		files["v1alpha1/"+name] = src
+	}
+
+	exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{
+		{
+			Name:  "golang.org/fake",
+			Files: files,
+		},
+		{
+			Name: "k8s.io/apimachinery",
+			Files: map[string]any{
+				"pkg/apis/meta/v1/meta.go": metaV1FixtureSource,
+			},
+		},
+		{
+			Name: "github.com/crossplane/crossplane",
+			Files: map[string]any{
+				"apis/v2/core/v2/types.go":          coreV2FixtureSource,
+				"apis/v2/core/v2/providerconfig.go": coreV2ProviderConfigFixtureSource,
+				"apis/v2/pkg/v1/types.go":           pkgV1FixtureSource,
+			},
+		},
+		{
+			Name: "github.com/crossplane/crossplane-runtime/v2",
+			Files: map[string]any{
+				"apis/common/v1/types.go": runtimeV1FixtureSource,
+				"apis/common/v2/types.go": runtimeV2FixtureSource,
+			},
+		},
+	})
+	t.Cleanup(exported.Cleanup)
+
+	exported.Config.Mode = LoadMode
+	pkgs, err := packages.Load(exported.Config, fmt.Sprintf("file=%s", exported.File("golang.org/fake", "v1alpha1/model.go")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 1 {
+		t.Fatalf("packages.Load() returned %d packages, want 1", len(pkgs))
+	}
+	for _, err := range pkgs[0].Errors {
+		t.Fatal(err)
+	}
+
+	return pkgs[0]
+}
+
+func generateOutput(t *testing.T, pkg *packages.Package, filename, generator string) string {
+	t.Helper()
+
+	g, ok := DefaultGenerators().Get(generator)
+	if !ok {
+		t.Fatalf("generator %q is not registered", generator)
+	}
+	files, err := GeneratePackages([]*packages.Package{pkg}, WithGenerators(NewRegistry(g)), WithFilename(g.Output, filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("GeneratePackages() returned %d files, want 1", len(files))
+	}
+	if got, want := files[0].Path, filepath.Join(filepath.Dir(pkg.GoFiles[0]), filename); got != want {
+		t.Fatalf("GeneratePackages() returned file %q, want %q", got, want)
+	}
+
+	return string(files[0].Contents)
+}
+
+func TestGeneratePackagesSharedOutput(t *testing.T) {
+	pkg := loadFixturePackage(t)
+
+	files, err := GeneratePackages([]*packages.Package{pkg})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var managed string
+	for _, f := range files {
+		if filepath.Base(f.Path) == "zz_generated.managed.go" {
+			managed = string(f.Contents)
+		}
+	}
+
+	// Every managed generator writes to the same output. Each must contribute
+	// its methods rather than overwriting those of the others.
+	for _, want := range []string{
+		`func (mg *LegacyResource) SetProviderConfigReference(r *xpv1.Reference) {`,
+		`func (mg *ModernResource) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {`,
+		`func (mg *ClusterResource) SetProviderConfigReference(r *xpv2.Reference) {`,
+		`func (mg *NamespacedResource) SetProviderConfigReference(r *xpv2.ProviderConfigReference) {`,
+	} {
+		if !strings.Contains(managed, want) {
+			t.Errorf("generated output missing snippet %q\n%s", want, managed)
+		}
+	}
+}
+
+func TestGeneratePackagesStale(t *testing.T) {
+	pkg := loadFixturePackage(t)
+	dir := filepath.Dir(pkg.GoFiles[0])
+
+	existing := map[string]string{
+		"zz_generated.stale.go":       "// Code generated by angryjet. DO NOT EDIT.\n\npackage v1alpha1\n",
+		"zz_generated.licensed.go":    "/*\nCopyright 2026 The Crossplane Authors.\n*/\n\n// Code generated by angryjet. DO NOT EDIT.\n\npackage v1alpha1\n",
+		"zz_generated.handwritten.go": "package v1alpha1\n",
+		"zz_generated.mentions.go":    "package v1alpha1\n\n// Code generated by angryjet. DO NOT EDIT.\n",
+		"zz_generated.foreign.go":     "// Code generated by controller-gen. DO NOT EDIT.\n\npackage v1alpha1\n",
+	}
+	for name, contents := range existing {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	never := MatcherFunc(func(_ types.Object) bool { return false })
+	methods := func(_ string, _ Comments) MethodSet { return MethodSet{} }
+	r := NewRegistry(
+		Generator{Name: "stale", Output: "stale", Matcher: never, Methods: methods},
+		Generator{Name: "licensed", Output: "licensed", Matcher: never, Methods: methods},
+		Generator{Name: "handwritten", Output: "handwritten", Matcher: never, Methods: methods},
+		Generator{Name: "mentions", Output: "mentions", Matcher: never, Methods: methods},
+		Generator{Name: "foreign", Output: "foreign", Matcher: never, Methods: methods},
+		Generator{Name: "missing", Output: "missing", Matcher: never, Methods: methods},
+	)
+
+	files, err := GeneratePackages([]*packages.Package{pkg}, WithGenerators(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []File{
+		{Path: filepath.Join(dir, "zz_generated.stale.go"), Stale: true},
+		{Path: filepath.Join(dir, "zz_generated.licensed.go"), Stale: true},
+	}
+	if diff := cmp.Diff(want, files); diff != "" {
+		t.Errorf("GeneratePackages(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestGeneratePackagesFilterExisting(t *testing.T) {
+	pkg := loadFixturePackage(t)
+
+	all, err := GeneratePackages([]*packages.Package{pkg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(all); err != nil {
+		t.Fatal(err)
+	}
+
+	// The managedlist and pculist outputs only have types the filter excludes,
+	// and the others have none.
+	files, err := GeneratePackages([]*packages.Package{pkg}, WithFilter(`!name("List$")`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(files); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]File{}
+	for _, f := range files {
+		got[filepath.Base(f.Path)] = f
+	}
+	for _, f := range all {
+		name := filepath.Base(f.Path)
+		switch name {
+		case "zz_generated.managedlist.go", "zz_generated.pculist.go":
+			if g, ok := got[name]; ok {
+				t.Errorf("GeneratePackages(...): want %s with filtered out types left as is, got %+v", name, g)
+			}
+		default:
+			if diff := cmp.Diff(f, got[name]); diff != "" {
+				t.Errorf("GeneratePackages(...): -want, +got %s:\n%s", name, diff)
+			}
+		}
+		b, err := os.ReadFile(f.Path)
+		if err != nil {
+			t.Errorf("Write(...): want %s on disk: %v", name, err)
+			continue
+		}
+		if diff := cmp.Diff(string(f.Contents), string(b)); diff != "" {
+			t.Errorf("Write(...): -want, +got %s:\n%s", name, diff)
+		}
+	}
+}
+
+func TestGeneratePackagesErrors(t *testing.T) {
+	pkgs := []*packages.Package{
+		{PkgPath: "example.org/broken/a", Errors: []packages.Error{{Pos: "a.go:1:1", Msg: "boom"}}},
+		loadFixturePackage(t),
+		{PkgPath: "example.org/broken/b", Errors: []packages.Error{{Pos: "b.go:2:1", Msg: "bang"}}},
+	}
+
+	files, err := GeneratePackages(pkgs, WithJobs(3))
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("GeneratePackages(...): want Errors, got %v", err)
+	}
+	want := []string{
+		"a.go:1:1: boom",
+		"b.go:2:1: bang",
+	}
+	got := make([]string, 0, len(errs))
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GeneratePackages(...): -want, +got errors:\n%s", diff)
+	}
+	if len(files) == 0 {
+		t.Errorf("GeneratePackages(...): want files for the packages that could be generated")
+	}
+}
+
+func TestGeneratePackagesErrorPositions(t *testing.T) {
+	pkg := loadFixturePackageWith(t, map[string]string{"broken.go": `package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+)
+
+type BrokenParameters struct {
+	// +crossplane:generate:reference:type=ReferenceTarget
+	// +crossplane:generate:reference:extractor=NotAFunction
+	Target string
+}
+
+type BrokenSpec struct {
+	xpv2.ClusterManagedResourceSpec
+	ForProvider BrokenParameters
+}
+
+type BrokenStatus struct {
+	xpv2.ManagedResourceStatus
+}
+
+type Broken struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   BrokenSpec
+	Status BrokenStatus
+}
+`})
+
+	files, err := GeneratePackages([]*packages.Package{pkg})
+
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("GeneratePackages(...): want one error, got %v", err)
+	}
+	got := errs.Error()
+	for _, want := range []string{"broken.go:11:2: ", "cannot render zz_generated.resolvers.go: cannot generate methods for Broken", `"NotAFunction" is not a valid function code`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("GeneratePackages(...): error %q does not contain %q", got, want)
+		}
+	}
+
+	// Outputs that don't involve the broken type are still generated.
+	for _, f := range files {
+		if filepath.Base(f.Path) == "zz_generated.resolvers.go" {
+			t.Errorf("GeneratePackages(...): want no resolvers for a package with a broken reference")
+		}
+	}
+	if len(files) == 0 {
+		t.Errorf("GeneratePackages(...): want files for outputs that could be generated")
+	}
+}
+
+func TestGeneratePackagesReferenceMarkers(t *testing.T) {
+	t.Run("Helpers", func(t *testing.T) {
+		pkg := loadFixturePackageWith(t, map[string]string{"helpers.go": `package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+)
+
+type HelpedParameters struct {
+	// +crossplane:generate:reference:type=ReferenceTarget
+	Target string
+}
+
+type HelpedSpec struct {
+	xpv2.ClusterManagedResourceSpec
+	ForProvider HelpedParameters
+}
+
+type HelpedStatus struct {
+	xpv2.ManagedResourceStatus
+}
+
+// +crossplane:generate:reference:helpers=true
+type Helped struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   HelpedSpec
+	Status HelpedStatus
+}
+`})
+
+		files, err := GeneratePackages([]*packages.Package{pkg})
+		if err != nil {
+			t.Fatalf("GeneratePackages(...): %v", err)
+		}
+		for _, f := range files {
+			if filepath.Base(f.Path) != "zz_generated.resolvers.go" {
+				continue
+			}
+			if want := "func (mg *Helped) resolveHelpedParametersReferences("; !strings.Contains(string(f.Contents), want) {
+				t.Errorf("GeneratePackages(...): want resolvers containing %q", want)
+			}
+			return
+		}
+		t.Errorf("GeneratePackages(...): want resolvers")
+	})
+
+	t.Run("RejectOutsideRoots", func(t *testing.T) {
+		pkg := loadFixturePackageWith(t, map[string]string{"rooted.go": `package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+)
+
+type RootedParameters struct {
+	// +crossplane:generate:reference:type=ReferenceTarget
+	Target string
+}
+
+type RootedSpec struct {
+	xpv2.ClusterManagedResourceSpec
+	ForProvider RootedParameters
+}
+
+type RootedStatus struct {
+	xpv2.ManagedResourceStatus
+	AtProvider RootedParameters
+}
+
+// +crossplane:generate:reference:roots=Spec.ForProvider
+// +crossplane:generate:reference:outsideRoots=reject
+type Rooted struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   RootedSpec
+	Status RootedStatus
+}
+`})
+
+		_, err := GeneratePackages([]*packages.Package{pkg})
+		if want := "cannot resolve references of field Status.AtProvider.Target"; err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("GeneratePackages(...): want error containing %q, got %v", want, err)
+		}
+	})
+
+	t.Run("UnknownRoot", func(t *testing.T) {
+		pkg := loadFixturePackageWith(t, map[string]string{"unknown.go": `package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+)
+
+type UnknownSpec struct {
+	xpv2.ClusterManagedResourceSpec
+}
+
+type UnknownStatus struct {
+	xpv2.ManagedResourceStatus
+}
+
+// +crossplane:generate:reference:roots=Spec.ForProvider,Spec.InitProvider
+type Unknown struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   UnknownSpec
+	Status UnknownStatus
+}
+`})
+
+		_, err := GeneratePackages([]*packages.Package{pkg})
+		if want := "unknown.go:16:1: cannot render zz_generated.resolvers.go: cannot generate methods for Unknown: cannot write method ResolveReferences: invalid crossplane:generate:reference:roots marker: type Unknown has no struct field Spec.ForProvider"; err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("GeneratePackages(...): want error containing %q, got %v", want, err)
+		}
+	})
+
+	t.Run("DefaultRoots", func(t *testing.T) {
+		pkg := loadFixturePackageWith(t, map[string]string{"defaulted.go": `package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	xpv2 "github.com/crossplane/crossplane/apis/v2/core/v2"
+)
+
+type DefaultedParameters struct {
+	// +crossplane:generate:reference:type=ReferenceTarget
+	Target string
+}
+
+type DefaultedSpec struct {
+	xpv2.ClusterManagedResourceSpec
+	ForProvider DefaultedParameters
+}
+
+type DefaultedStatus struct {
+	xpv2.ManagedResourceStatus
+	AtProvider DefaultedParameters
+}
+
+type Defaulted struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   DefaultedSpec
+	Status DefaultedStatus
+}
+`})
+
+		files, err := GeneratePackages([]*packages.Package{pkg}, WithReferenceRoots("Spec.ForProvider", "Spec.InitProvider"))
+		if err != nil {
+			t.Fatalf("GeneratePackages(...): %v", err)
+		}
+		for _, f := range files {
+			if filepath.Base(f.Path) != "zz_generated.resolvers.go" {
+				continue
+			}
+			got := string(f.Contents)
+			if want := "mg.Spec.ForProvider.Target"; !strings.Contains(got, want) {
+				t.Errorf("GeneratePackages(...): want resolvers containing %q, got:\n%s", want, got)
+			}
+			if notWant := "mg.Status.AtProvider.Target"; strings.Contains(got, notWant) {
+				t.Errorf("GeneratePackages(...): want resolvers without %q outside the default roots, got:\n%s", notWant, got)
+			}
+			return
+		}
+		t.Errorf("GeneratePackages(...): want resolvers")
+	})
+}
+
+func TestGeneratePackagesMarkerSeverity(t *testing.T) {
+	pkg := loadFixturePackageWith(t, map[string]string{"typo.go": `package v1alpha1
+
+// +crossplane:generate:method=false
+type Typo struct{}
+`})
+	want := `typo.go:3:1: unknown marker "crossplane:generate:method", did you mean "crossplane:generate:methods"?`
+
+	t.Run("Warning", func(t *testing.T) {
+		warnings := []string{}
+		files, err := GeneratePackages([]*packages.Package{pkg}, WithWarnings(func(w error) {
+			warnings = append(warnings, Format(w))
+		}))
+		if err != nil {
+			t.Fatalf("GeneratePackages(...): %v", err)
+		}
+		if len(files) == 0 {
+			t.Errorf("GeneratePackages(...): want files despite warnings")
+		}
+		if len(warnings) != 1 || !strings.HasSuffix(warnings[0], want) {
+			t.Errorf("GeneratePackages(...): want warning %q, got %q", want, warnings)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		files, err := GeneratePackages([]*packages.Package{pkg}, WithMarkerSeverity(SeverityError))
+		if err == nil || !strings.HasSuffix(err.Error(), want) {
+			t.Errorf("GeneratePackages(...): want error %q, got %v", want, err)
+		}
+		if len(files) != 0 {
+			t.Errorf("GeneratePackages(...): want no files for a package with malformed markers")
+		}
+	})
+}
+
+func TestGeneratePackagesNearMisses(t *testing.T) {
+	pkg := loadFixturePackageWith(t, map[string]string{"nearmiss.go": `package v1alpha1
+
+import (
+	xprv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type AlmostSpec struct {
+	xprv1.ResourceSpec
+}
+
+type AlmostStatus struct {
+	Status xprv1.ResourceStatus
+}
+
+type Almost struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   AlmostSpec
+	Status AlmostStatus
+}
+
+// +crossplane:generate:methods=false
+type AlmostDisabled struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   AlmostSpec
+	Status AlmostStatus
+}
+
+type Unrelated struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   struct{}
+	Status struct{}
+}
+`})
+
+	cases := map[string]struct {
+		opts []Option
+		want []string
+	}{
+		"NearMiss": {
+			want: []string{
+				"nearmiss.go:16:6: type Almost nearly matches generator managed-legacy, but is missing field Status: embedded github.com/crossplane/crossplane-runtime/v2/apis/common/v1.ResourceStatus",
+			},
+		},
+		"OtherFlavorDisabled": {
+			// The fixture's legacy resource nearly matches the modern
+			// flavor, but isn't a near miss because the disabled legacy
+			// flavor matches it.
+			opts: []Option{WithDisabledGenerators(KindManaged+"-"+FlavorLegacy, "references-"+FlavorLegacy)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			opts := append([]Option{WithWarnings(func(w error) {
+				got = append(got, Format(w))
+			})}, tc.opts...)
+			if _, err := GeneratePackages([]*packages.Package{pkg}, opts...); err != nil {
+				t.Fatalf("GeneratePackages(...): %v", err)
+			}
+			for i := range got {
+				got[i] = strings.TrimPrefix(got[i], filepath.Dir(pkg.GoFiles[0])+string(filepath.Separator))
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("GeneratePackages(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGeneratePackagesDeclaredKind(t *testing.T) {
+	const common = `package v1alpha1
+
+import (
+	xprv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type DeclaredCommon struct {
+	xprv1.ResourceSpec
+}
+
+type DeclaredSpec struct {
+	DeclaredCommon
+}
+
+type DeclaredStatus struct {
+	xprv1.ResourceStatus
+}
+
+type DeclaredNoStatus struct{}
+
+var _ metav1.TypeMeta
+`
+
+	cases := map[string]struct {
+		src     string
+		want    string
+		wantErr string
+	}{
+		"IndirectSpec": {
+			src: `
+// +crossplane:generate:kind=managed
+type Declared struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   DeclaredSpec
+	Status DeclaredStatus
+}
+`,
+			want: "func (mg *Declared) SetConditions(",
+		},
+		"Unreachable": {
+			src: `
+// +crossplane:generate:kind=managed
+// +crossplane:generate:flavor=legacy
+type Declared struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   DeclaredSpec
+	Status DeclaredNoStatus
+}
+`,
+			wantErr: "type Declared declares kind managed and flavor legacy, but does not have field Status: embedded github.com/crossplane/crossplane-runtime/v2/apis/common/v1.ResourceStatus",
+		},
+		"FlavorWithoutKind": {
+			src: `
+// +crossplane:generate:flavor=legacy
+type Declared struct{}
+`,
+			wantErr: "type Declared declares a flavor using +crossplane:generate:flavor, but not a kind",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pkg := loadFixturePackageWith(t, map[string]string{"declared.go": common + tc.src})
+			if tc.wantErr != "" {
+				_, err := GeneratePackages([]*packages.Package{pkg})
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("GeneratePackages(...): want error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			got := generateOutput(t, pkg, "zz_generated.managed.go", KindManaged+"-"+FlavorLegacy)
+			if !strings.Contains(got, tc.want) {
+				t.Errorf("GeneratePackages(...): want output containing %q, got:\n%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestGeneratePackagesEmbeddingChain(t *testing.T) {
+	pkg := loadFixturePackageWith(t, map[string]string{"chain.go": `package v1alpha1
+
+import (
+	xprv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ChainedBase struct {
+	xprv1.ResourceSpec
+}
+
+type ChainedCommon struct {
+	ChainedBase
+}
+
+type ChainedSpec struct {
+	ChainedCommon
+}
+
+type ChainedStatus struct {
+	xprv1.ResourceStatus
+}
+
+type Chained struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   ChainedSpec
+	Status ChainedStatus
+}
+`})
+
+	got := generateOutput(t, pkg, "zz_generated.managed.go", KindManaged+"-"+FlavorLegacy)
+	for _, want := range []string{
+		"func (mg *Chained) GetDeletionPolicy() xpv1.DeletionPolicy {\n\treturn mg.Spec.DeletionPolicy\n}",
+		"func (mg *Chained) SetConditions(c ...xpv1.Condition) {\n\tmg.Status.SetConditions(c...)\n}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("GeneratePackages(...): want output containing %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestGeneratePackagesModulePath(t *testing.T) {
+	exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{
+		{
+			Name: "golang.org/fake",
+			Files: map[string]any{"v1alpha1/model.go": `package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	xpv2 "example.org/crossplane/apis/v2/core/v2"
+)
+
+type ForkedSpec struct {
+	xpv2.ManagedResourceSpec
+}
+
+type ForkedStatus struct {
+	xpv2.ManagedResourceStatus
+}
+
+type Forked struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   ForkedSpec
+	Status ForkedStatus
+}
+`},
+		},
+		{
+			Name:  "k8s.io/apimachinery",
+			Files: map[string]any{"pkg/apis/meta/v1/meta.go": metaV1FixtureSource},
+		},
+		{
+			Name: "example.org/crossplane",
+			Files: map[string]any{
+				"apis/v2/core/v2/types.go":          coreV2FixtureSource,
+				"apis/v2/core/v2/providerconfig.go": coreV2ProviderConfigFixtureSource,
+			},
+		},
+	})
+	t.Cleanup(exported.Cleanup)
+	exported.Config.Mode = LoadMode
+	pkgs, err := packages.Load(exported.Config, fmt.Sprintf("file=%s", exported.File("golang.org/fake", "v1alpha1/model.go")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, _ := DefaultGenerators().Get(KindManaged + "-" + FlavorModernCore)
+	files, err := GeneratePackages(pkgs, WithGenerators(NewRegistry(g)), WithModulePath("github.com/crossplane/crossplane/apis/v2", "example.org/crossplane/apis/v2"))
+	if err != nil {
+		t.Fatalf("GeneratePackages(...): %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("GeneratePackages(...): want 1 file, got %d", len(files))
+	}
+	got := string(files[0].Contents)
+	for _, want := range []string{
+		`xpv2 "example.org/crossplane/apis/v2/core/v2"`,
+		`func (mg *Forked) GetProviderConfigReference() *xpv2.ProviderConfigReference {`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("GeneratePackages(...): want output containing %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "github.com/crossplane/crossplane/apis") {
+		t.Errorf("GeneratePackages(...): want no imports of the replaced module, got:\n%s", got)
+	}
+
+	files, err = GeneratePackages(pkgs, WithGenerators(NewRegistry(g)))
+	if err != nil {
+		t.Fatalf("GeneratePackages(...): %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("GeneratePackages(...): want no files without the module path, got %d", len(files))
+	}
+}
+
+func TestGeneratePackagesConcurrentModulePaths(t *testing.T) {
+	exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{
+		{
+			Name: "golang.org/fake",
+			Files: map[string]any{"v1alpha1/model.go": `package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	acme "example.org/acme/apis/v2/core/v2"
+	xpv2 "example.org/crossplane/apis/v2/core/v2"
+)
+
+type ForkedSpec struct {
+	xpv2.ManagedResourceSpec
+}
+
+type ForkedStatus struct {
+	xpv2.ManagedResourceStatus
+}
+
+type Forked struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   ForkedSpec
+	Status ForkedStatus
+}
+
+type AcmeSpec struct {
+	acme.ManagedResourceSpec
+}
+
+type AcmeStatus struct {
+	acme.ManagedResourceStatus
+}
+
+type Acme struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   AcmeSpec
+	Status AcmeStatus
+}
+`},
+		},
+		{
+			Name:  "k8s.io/apimachinery",
+			Files: map[string]any{"pkg/apis/meta/v1/meta.go": metaV1FixtureSource},
+		},
+		{
+			Name: "example.org/crossplane",
+			Files: map[string]any{
+				"apis/v2/core/v2/types.go":          coreV2FixtureSource,
+				"apis/v2/core/v2/providerconfig.go": coreV2ProviderConfigFixtureSource,
+			},
+		},
+		{
+			Name: "example.org/acme",
+			Files: map[string]any{
+				"apis/v2/core/v2/types.go":          coreV2FixtureSource,
+				"apis/v2/core/v2/providerconfig.go": coreV2ProviderConfigFixtureSource,
+			},
+		},
+	})
+	t.Cleanup(exported.Cleanup)
+	exported.Config.Mode = LoadMode
+	pkgs, err := packages.Load(exported.Config, fmt.Sprintf("file=%s", exported.File("golang.org/fake", "v1alpha1/model.go")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Generate the package many times, so that the two generations overlap.
+	many := make([]*packages.Package, 0, 100)
+	for range cap(many) {
+		many = append(many, pkgs...)
+	}
+	g, _ := DefaultGenerators().Get(KindManaged + "-" + FlavorModernCore)
+
+	cases := map[string]struct {
+		reason      string
+		replacement string
+		want        string
+		notWant     string
+	}{
+		"Crossplane": {
+			reason:      "Only the types of the module that replaces the core API module should match.",
+			replacement: "example.org/crossplane/apis/v2",
+			want:        "func (mg *Forked) GetProviderConfigReference()",
+			notWant:     "func (mg *Acme) ",
+		},
+		"Acme": {
+			reason:      "Only the types of the module that replaces the core API module should match.",
+			replacement: "example.org/acme/apis/v2",
+			want:        "func (mg *Acme) GetProviderConfigReference()",
+			notWant:     "func (mg *Forked) ",
+		},
+	}
+	// Each case generates concurrently with the others, so each must match
+	// using only its own module path.
+	wg := &sync.WaitGroup{}
+	for name, tc := range cases {
+		wg.Go(func() {
+			files, err := GeneratePackages(many, WithJobs(4), WithGenerators(NewRegistry(g)), WithModulePath("github.com/crossplane/crossplane/apis/v2", tc.replacement))
+			if err != nil {
+				t.Errorf("%s\n%s\nGeneratePackages(...): %v", name, tc.reason, err)
+				return
+			}
+			if len(files) != len(many) {
+				t.Errorf("%s\n%s\nGeneratePackages(...): want %d files, got %d", name, tc.reason, len(many), len(files))
+				return
+			}
+			for _, f := range files {
+				got := string(f.Contents)
+				if !strings.Contains(got, tc.want) || strings.Contains(got, tc.notWant) {
+					t.Errorf("%s\n%s\nGeneratePackages(...): want output containing %q and not %q, got:\n%s", name, tc.reason, tc.want, tc.notWant, got)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package angryjet
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-tools/internal/diff"
+)
+
+// An Action that writing a generated file would take.
+type Action string
+
+// Actions.
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionDelete    Action = "delete"
+	ActionUnchanged Action = "unchanged"
+)
+
+// A Change describes how writing a generated file would change what is
+// currently on disk.
+type Change struct {
+	File
+
+	// Action that writing the file would take.
+	Action Action
+
+	// Current contents of the file on disk, if any.
+	Current []byte
+}
+
+// Diff returns a unified diff between the current and generated contents of
+// the file. It returns an empty string if the file is unchanged.
+func (c Change) Diff() string {
+	from, to := c.Path, c.Path
+	switch c.Action {
+	case ActionCreate:
+		from = os.DevNull
+	case ActionDelete:
+		to = os.DevNull
+	case ActionUpdate, ActionUnchanged:
+	}
+	return diff.Unified(from, to, c.Current, c.Contents)
+}
+
+// Plan returns the changes that writing the supplied files would make.
+func Plan(files []File) ([]Change, error) {
+	changes := make([]Change, 0, len(files))
+	for _, f := range files {
+		c := Change{File: f, Action: ActionUnchanged}
+		cur, err := os.ReadFile(f.Path)
+		switch {
+		case errors.Is(err, os.ErrNotExist) && f.Stale:
+		case errors.Is(err, os.ErrNotExist):
+			c.Action = ActionCreate
+		case err != nil:
+			return nil, errors.Wrapf(err, "cannot read %s", f.Path)
+		case f.Stale:
+			c.Action = ActionDelete
+		case !bytes.Equal(cur, f.Contents):
+			c.Action = ActionUpdate
+		}
+		c.Current = cur
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// Verify returns an error listing each of the supplied files that is missing
+// from disk, differs from what is on disk, or is stale. It does not write any files.
+func Verify(files []File) error {
+	changes, err := Plan(files)
+	if err != nil {
+		return err
+	}
+	stale := make([]string, 0)
+	for _, c := range changes {
+		switch c.Action {
+		case ActionCreate:
+			stale = append(stale, fmt.Sprintf("%s is missing", c.Path))
+		case ActionUpdate:
+			stale = append(stale, fmt.Sprintf("%s is out of date", c.Path))
+		case ActionDelete:
+			stale = append(stale, fmt.Sprintf("%s should be deleted", c.Path))
+		case ActionUnchanged:
+		}
+	}
+	if len(stale) > 0 {
+		return errors.Errorf("generated files are stale:\n%s", strings.Join(stale, "\n"))
+	}
+	return nil
+}
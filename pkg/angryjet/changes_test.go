@@ -0,0 +1,110 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package angryjet
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlan(t *testing.T) {
+	dir := t.TempDir()
+	existing := map[string]string{
+		"zz_generated.unchanged.go": "package v1\n",
+		"zz_generated.updated.go":   "package v1\n",
+		"zz_generated.stale.go":     "package v1\n",
+	}
+	for name, contents := range existing {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files := []File{
+		{Path: filepath.Join(dir, "zz_generated.unchanged.go"), Contents: []byte("package v1\n")},
+		{Path: filepath.Join(dir, "zz_generated.updated.go"), Contents: []byte("package v1\n\nfunc F() {}\n")},
+		{Path: filepath.Join(dir, "zz_generated.created.go"), Contents: []byte("package v1\n")},
+		{Path: filepath.Join(dir, "zz_generated.stale.go"), Stale: true},
+		{Path: filepath.Join(dir, "zz_generated.gone.go"), Stale: true},
+	}
+	changes, err := Plan(files)
+	if err != nil {
+		t.Fatalf("Plan(...): %v", err)
+	}
+
+	got := map[string]Action{}
+	for _, c := range changes {
+		got[filepath.Base(c.Path)] = c.Action
+	}
+	want := map[string]Action{
+		"zz_generated.unchanged.go": ActionUnchanged,
+		"zz_generated.updated.go":   ActionUpdate,
+		"zz_generated.created.go":   ActionCreate,
+		"zz_generated.stale.go":     ActionDelete,
+		"zz_generated.gone.go":      ActionUnchanged,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Plan(...): -want, +got:\n%s", diff)
+	}
+
+	for _, c := range changes {
+		if (c.Diff() == "") != (c.Action == ActionUnchanged) {
+			t.Errorf("%s: Diff() returned %q for action %s", c.Path, c.Diff(), c.Action)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, "zz_generated.current.go")
+	if err := os.WriteFile(current, []byte("package v1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		files []File
+		want  []string
+	}{
+		"UpToDate": {
+			files: []File{{Path: current, Contents: []byte("package v1\n")}},
+		},
+		"Stale": {
+			files: []File{
+				{Path: current, Contents: []byte("package v2\n")},
+				{Path: filepath.Join(dir, "zz_generated.missing.go"), Contents: []byte("package v1\n")},
+			},
+			want: []string{"zz_generated.current.go is out of date", "zz_generated.missing.go is missing"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Verify(tc.files)
+			if (err != nil) != (len(tc.want) > 0) {
+				t.Fatalf("Verify(...): unexpected error %v", err)
+			}
+			for _, w := range tc.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("Verify(...): error %q does not contain %q", err, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package angryjet
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigFilename is the name of the file angryjet reads its configuration
+// from, at the root of a Go module.
+const ConfigFilename = "angryjet.yaml"
+
+// A Config configures angryjet. It is typically read from an angryjet.yaml file
+// at the root of a Go module, for example:
+//
+//	packages:
+//	- ./apis/...
+//	headerFile: hack/boilerplate.go.txt
+//	acceptedModules:
+//	  github.com/crossplane/crossplane-runtime/v2:
+//	  - github.com/acme/crossplane-runtime/v2
+//	modulePaths:
+//	  github.com/crossplane/crossplane/apis/v2: github.com/acme/crossplane/apis/v2
+//	filenames:
+//	  resolvers: zz_generated.refs.go
+//	referenceRoots:
+//	- Spec.ForProvider
+//	- Spec.InitProvider
+//	importAliases:
+//	  github.com/crossplane/crossplane-runtime/v2/apis/common/v1: commonv1
+//	generators:
+//	  disabled:
+//	  - references-legacy
+//	overrides:
+//	- package: example.org/provider/apis/legacy/...
+//	  generators:
+//	    enabled:
+//	    - references-legacy
+//	  filter: '!path("/deprecated/")'
+type Config struct {
+	// Packages for which to generate methods, relative to the directory that
+	// contains the config file.
+	Packages []string `json:"packages,omitempty"`
+
+	// HeaderFile whose contents are added to the top of all generated files,
+	// relative to the directory that contains the config file.
+	HeaderFile string `json:"headerFile,omitempty"`
+
+	// AcceptedModules whose types are accepted in place of those of another
+	// module, keyed by the path of that module. See WithAcceptedModules.
+	AcceptedModules map[string][]string `json:"acceptedModules,omitempty"`
+
+	// ModulePaths of modules whose packages are used in place of those of
+	// another module, keyed by the path of that module. See WithModulePath.
+	ModulePaths map[string]string `json:"modulePaths,omitempty"`
+
+	Settings `json:",inline"`
+
+	// Overrides of settings for particular packages. Overrides are applied in
+	// order, after the top-level settings.
+	Overrides []Override `json:"overrides,omitempty"`
+}
+
+// Settings that may be configured for all packages, or overridden for some.
+type Settings struct {
+	// Filenames of generated files, keyed by generator output, for example
+	// managed or resolvers. See WithFilename.
+	Filenames map[string]string `json:"filenames,omitempty"`
+
+	// ImportAliases used by generated code, keyed by import path. See
+	// WithImportAliases.
+	ImportAliases map[string]string `json:"importAliases,omitempty"`
+
+	// Generators to run.
+	Generators GeneratorSettings `json:"generators,omitempty"`
+
+	// Filter restricts generation to the types that match an expression. See
+	// WithFilter.
+	Filter string `json:"filter,omitempty"`
+
+	// ReferenceRoots of managed resources that don't specify their own using
+	// the reference:roots marker. See WithReferenceRoots.
+	ReferenceRoots []string `json:"referenceRoots,omitempty"`
+}
+
+// GeneratorSettings configure which generators run, by name.
+type GeneratorSettings struct {
+	// Enabled generators. All generators are enabled if none are specified.
+	Enabled []string `json:"enabled,omitempty"`
+
+	// Disabled generators.
+	Disabled []string `json:"disabled,omitempty"`
+}
+
+// An Override of settings for the packages that match a pattern.
+type Override struct {
+	// Package import path pattern. A pattern may contain '...' wildcards, for
+	// example example.org/provider/apis/....
+	Package string `json:"package"`
+
+	Settings `json:",inline"`
+}
+
+// FindConfig returns the path of the config file at the root of the Go module
+// that contains the supplied directory. It returns an empty string if the
+// module has no config file, or the directory is not within a module.
+func FindConfig(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot determine absolute path")
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			path := filepath.Join(dir, ConfigFilename)
+			if _, err := os.Stat(path); err != nil {
+				return "", nil
+			}
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadConfig reads the config file at the supplied path.
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path) //nolint:gosec // Reading the supplied config file is the point.
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read %s", path)
+	}
+	c := &Config{}
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return nil, errors.Wrapf(err, "cannot parse %s", path)
+	}
+	for _, o := range c.Overrides {
+		if o.Package == "" {
+			return nil, errors.Errorf("cannot parse %s: overrides must specify a package", path)
+		}
+	}
+	return c, nil
+}
+
+// Options returns the generation options configured by the supplied config.
+// Relative paths are resolved against the supplied directory, which is
+// typically the directory that contains the config file.
+func (c *Config) Options(dir string) ([]Option, error) {
+	o := c.Settings.Options()
+	if c.HeaderFile != "" {
+		path := c.HeaderFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		h, err := os.ReadFile(path) //nolint:gosec // Reading the configured header file is the point.
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read header file %s", path)
+		}
+		o = append(o, WithHeader(string(h)))
+	}
+	for module, forks := range c.AcceptedModules {
+		o = append(o, WithAcceptedModules(module, forks...))
+	}
+	for module, replacement := range c.ModulePaths {
+		o = append(o, WithModulePath(module, replacement))
+	}
+	for _, ov := range c.Overrides {
+		o = append(o, WithPackageOptions(ov.Package, ov.Settings.Options()...))
+	}
+	return o, nil
+}
+
+// Options returns the generation options configured by the supplied settings.
+func (s Settings) Options() []Option {
+	o := make([]Option, 0, len(s.Filenames)+5)
+	for output, filename := range s.Filenames {
+		o = append(o, WithFilename(output, filename))
+	}
+	if len(s.ImportAliases) > 0 {
+		o = append(o, WithImportAliases(s.ImportAliases))
+	}
+	if len(s.Generators.Enabled) > 0 {
+		o = append(o, WithEnabledGenerators(s.Generators.Enabled...))
+	}
+	if len(s.Generators.Disabled) > 0 {
+		o = append(o, WithDisabledGenerators(s.Generators.Disabled...))
+	}
+	if s.Filter != "" {
+		o = append(o, WithFilter(s.Filter))
+	}
+	if len(s.ReferenceRoots) > 0 {
+		o = append(o, WithReferenceRoots(s.ReferenceRoots...))
+	}
+	return o
+}
+
+// matchPattern returns true if the supplied package import path matches the
+// supplied pattern. As with the go command, '...' matches any string, and a
+// trailing '/...' also matches the path before it.
+func matchPattern(pattern, path string) bool {
+	re := regexp.QuoteMeta(pattern)
+	if strings.HasSuffix(re, `/\.\.\.`) {
+		re = strings.TrimSuffix(re, `/\.\.\.`) + `(/.*)?`
+	}
+	re = strings.ReplaceAll(re, `\.\.\.`, `.*`)
+	return regexp.MustCompile(`^` + re + `$`).MatchString(path)
+}
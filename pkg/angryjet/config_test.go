@@ -0,0 +1,269 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package angryjet
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cases := map[string]struct {
+		config string
+		want   *Config
+		err    string
+	}{
+		"Full": {
+			config: `
+packages:
+- ./apis/...
+headerFile: hack/boilerplate.go.txt
+acceptedModules:
+  github.com/crossplane/crossplane-runtime/v2:
+  - github.com/acme/crossplane-runtime/v2
+modulePaths:
+  github.com/crossplane/crossplane/apis/v2: github.com/acme/crossplane/apis/v2
+filenames:
+  resolvers: zz_generated.refs.go
+referenceRoots:
+- Spec.ForProvider
+- Spec.InitProvider
+importAliases:
+  github.com/crossplane/crossplane-runtime/v2/apis/common/v1: commonv1
+generators:
+  disabled:
+  - references-legacy
+overrides:
+- package: example.org/provider/apis/legacy/...
+  generators:
+    enabled:
+    - references-legacy
+  filter: '!path("/deprecated/")'
+`,
+			want: &Config{
+				Packages:   []string{"./apis/..."},
+				HeaderFile: "hack/boilerplate.go.txt",
+				AcceptedModules: map[string][]string{
+					"github.com/crossplane/crossplane-runtime/v2": {"github.com/acme/crossplane-runtime/v2"},
+				},
+				ModulePaths: map[string]string{
+					"github.com/crossplane/crossplane/apis/v2": "github.com/acme/crossplane/apis/v2",
+				},
+				Settings: Settings{
+					Filenames:      map[string]string{"resolvers": "zz_generated.refs.go"},
+					ImportAliases:  map[string]string{"github.com/crossplane/crossplane-runtime/v2/apis/common/v1": "commonv1"},
+					Generators:     GeneratorSettings{Disabled: []string{"references-legacy"}},
+					ReferenceRoots: []string{"Spec.ForProvider", "Spec.InitProvider"},
+				},
+				Overrides: []Override{{
+					Package: "example.org/provider/apis/legacy/...",
+					Settings: Settings{
+						Generators: GeneratorSettings{Enabled: []string{"references-legacy"}},
+						Filter:     `!path("/deprecated/")`,
+					},
+				}},
+			},
+		},
+		"UnknownField": {
+			config: "filename:\n  managed: zz.go\n",
+			err:    `unknown field "filename"`,
+		},
+		"OverrideWithoutPackage": {
+			config: "overrides:\n- filenames:\n    managed: zz.go\n",
+			err:    "overrides must specify a package",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ConfigFilename)
+			if err := os.WriteFile(path, []byte(tc.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadConfig(path)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("LoadConfig(...): want error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("LoadConfig(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFindConfig(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "apis", "v1")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.org/provider\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := FindConfig(dir)
+	if err != nil || got != "" {
+		t.Errorf("FindConfig(...): want no config, got %q, %v", got, err)
+	}
+
+	want := filepath.Join(root, ConfigFilename)
+	if err := os.WriteFile(want, []byte("{}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err = FindConfig(dir)
+	if err != nil || got != want {
+		t.Errorf("FindConfig(...): want %q, got %q, %v", want, got, err)
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	cases := map[string]struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		"Exact":          {pattern: "example.org/apis/v1", path: "example.org/apis/v1", want: true},
+		"Different":      {pattern: "example.org/apis/v1", path: "example.org/apis/v2", want: false},
+		"TrailingParent": {pattern: "example.org/apis/...", path: "example.org/apis", want: true},
+		"TrailingChild":  {pattern: "example.org/apis/...", path: "example.org/apis/ec2/v1", want: true},
+		"TrailingPrefix": {pattern: "example.org/apis/...", path: "example.org/apisv2", want: false},
+		"Infix":          {pattern: "example.org/.../v1", path: "example.org/apis/ec2/v1", want: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := matchPattern(tc.pattern, tc.path); got != tc.want {
+				t.Errorf("matchPattern(%q, %q): want %t, got %t", tc.pattern, tc.path, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestGeneratePackagesSettings(t *testing.T) {
+	pkg := loadFixturePackage(t)
+
+	outputs := func(files []File) []string {
+		got := make([]string, 0, len(files))
+		for _, f := range files {
+			got = append(got, filepath.Base(f.Path))
+		}
+		return got
+	}
+
+	cases := map[string]struct {
+		o    []Option
+		want []string
+		err  string
+	}{
+		"Enabled": {
+			o:    []Option{WithEnabledGenerators("managedlist-modern-core")},
+			want: []string{"zz_generated.managedlist.go"},
+		},
+		"Disabled": {
+			o: []Option{WithDisabledGenerators(
+				"managed-legacy", "managed-modern", "managed-legacy-core", "managed-modern-core",
+				"references-legacy", "references-modern", "references-legacy-core", "references-modern-core",
+				"providerconfig-legacy", "providerconfig-core", "clusterproviderconfig-core",
+				"providerconfigusage-legacy", "providerconfigusage-modern", "providerconfigusage-legacy-core", "providerconfigusage-modern-core",
+				"providerconfigusagelist-legacy", "providerconfigusagelist-modern", "providerconfigusagelist-legacy-core", "providerconfigusagelist-modern-core",
+				"composite-core", "claim-core", "package-core", "packagerevision-core",
+			)},
+			want: []string{"zz_generated.managedlist.go"},
+		},
+		"MatchingPackageOverride": {
+			o: []Option{
+				WithEnabledGenerators("managedlist-modern-core"),
+				WithPackageOptions("golang.org/fake/...", WithFilename(OutputManagedList, "zz_generated.lists.go")),
+			},
+			want: []string{"zz_generated.lists.go"},
+		},
+		"OtherPackageOverride": {
+			o: []Option{
+				WithEnabledGenerators("managedlist-modern-core"),
+				WithPackageOptions("example.org/...", WithFilename(OutputManagedList, "zz_generated.lists.go")),
+			},
+			want: []string{"zz_generated.managedlist.go"},
+		},
+		"Filter": {
+			o:    []Option{WithFilter(`!name("List$")`)},
+			want: []string{"zz_generated.managed.go", "zz_generated.pc.go", "zz_generated.pcu.go", "zz_generated.resolvers.go", "zz_generated.composite.go", "zz_generated.package.go"},
+		},
+		"FilterEveryOutput": {
+			o:    []Option{WithFilter(`matches("managed-modern-core") || name("^NamespacedResourceList$")`)},
+			want: []string{},
+		},
+		"FilterOtherPackages": {
+			o:    []Option{WithFilter(`path("^example.org/")`)},
+			want: []string{},
+		},
+		"InvalidFilter": {
+			o:   []Option{WithFilter(`matches("managed-futuristic")`)},
+			err: `unknown matcher "managed-futuristic"`,
+		},
+		"UnknownGenerator": {
+			o:   []Option{WithDisabledGenerators("managed-futuristic")},
+			err: `unknown generator "managed-futuristic"`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			files, err := GeneratePackages([]*packages.Package{pkg}, tc.o...)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("GeneratePackages(...): want error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GeneratePackages(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, outputs(files)); diff != "" {
+				t.Errorf("GeneratePackages(...): -want, +got outputs:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGeneratePackagesImportAliases(t *testing.T) {
+	pkg := loadFixturePackage(t)
+
+	files, err := GeneratePackages([]*packages.Package{pkg},
+		WithEnabledGenerators("managed-legacy"),
+		WithImportAliases(map[string]string{RuntimeImport: "commonv1"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("GeneratePackages(...): want 1 file, got %d", len(files))
+	}
+	got := string(files[0].Contents)
+	for _, want := range []string{`commonv1 "` + RuntimeImport + `"`, "*commonv1.Reference"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("GeneratePackages(...): output does not contain %q\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package angryjet
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/crossplane/crossplane-tools/internal/comments"
+	"github.com/crossplane/crossplane-tools/internal/match"
+	"github.com/crossplane/crossplane-tools/internal/method"
+)
+
+// A ClauseResult is the result of evaluating one clause of a matcher against
+// an object.
+type ClauseResult = match.Result
+
+// An Explanation of whether and why generators would generate methods for a
+// type.
+type Explanation struct {
+	// Type the explanation is for.
+	Type string
+
+	// Position at which the type is defined.
+	Position token.Position
+
+	// Excluded is true if the filter excludes the type from generation. See
+	// WithFilter.
+	Excluded bool
+
+	// Generators, in the order they run.
+	Generators []GeneratorExplanation
+}
+
+// A GeneratorExplanation explains whether and why a generator would generate
+// methods for a type.
+type GeneratorExplanation struct {
+	// Generator the explanation is for.
+	Generator string
+
+	// Matched is true if the generator's matcher matched the type.
+	Matched bool
+
+	// Disabled is true if the type disables generation using the
+	// DisableMarker.
+	Disabled bool
+
+	// Declared is true if the type declares its kind using the KindMarker.
+	// Types that declare their kind are matched by the generators of that
+	// kind and flavor, and fields promoted from embedded structs satisfy
+	// their clauses.
+	Declared bool
+
+	// WrongScope is the scope of the type if the generator only produces
+	// methods for resources of another scope. See Generator.Scope.
+	WrongScope string
+
+	// Clauses are the results of each clause of the generator's matcher. They
+	// are only available for matchers that can explain themselves.
+	Clauses []ClauseResult
+
+	// Methods the generator would write for the type, if it matched.
+	Methods []MethodExplanation
+}
+
+// A MethodExplanation explains whether a method would be generated.
+type MethodExplanation struct {
+	// Method name.
+	Method string
+
+	// DefinedAt is the position of an existing definition of the method,
+	// outside of the file it would be generated in. It is not valid if the
+	// method is not defined outside of that file.
+	DefinedAt token.Position
+}
+
+// Skipped returns true if the method would not be generated because it is
+// already defined outside of the file it would be generated in.
+func (m MethodExplanation) Skipped() bool {
+	return m.DefinedAt.IsValid()
+}
+
+// Explain loads the package matching the supplied pattern and explains whether
+// and why each generator would generate methods for each of its types, or only
+// for the named type if a name is supplied.
+func Explain(cfg *packages.Config, pattern, typeName string, o ...Option) ([]Explanation, error) {
+	c := &packages.Config{}
+	if cfg != nil {
+		*c = *cfg
+	}
+	c.Mode |= LoadMode
+
+	pkgs, err := packages.Load(c, pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot load package %s", pattern)
+	}
+	if len(pkgs) != 1 {
+		return nil, errors.Errorf("pattern %s must match exactly one package, not %d", pattern, len(pkgs))
+	}
+	return ExplainPackage(pkgs[0], typeName, o...)
+}
+
+// ExplainPackage explains whether and why each generator would generate methods
+// for each type of the supplied package, or only for the named type if a name
+// is supplied. The package must have been loaded using at least LoadMode.
+func ExplainPackage(p *packages.Package, typeName string, o ...Option) ([]Explanation, error) {
+	if len(p.Errors) > 0 {
+		return nil, Errors{}.Append(p.Errors[0])
+	}
+	if len(p.GoFiles) == 0 {
+		return nil, errors.Errorf("package %s has no Go files", p.PkgPath)
+	}
+	base, err := newOptions(o...)
+	if err != nil {
+		return nil, err
+	}
+	opts := base.forPackage(p.PkgPath)
+	if err := opts.validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid options for package %s", p.PkgPath)
+	}
+
+	names := p.Types.Scope().Names()
+	if typeName != "" {
+		if _, ok := p.Types.Scope().Lookup(typeName).(*types.TypeName); !ok {
+			return nil, errors.Errorf("package %s has no type %s", p.PkgPath, typeName)
+		}
+		names = []string{typeName}
+	}
+
+	c := comments.In(p)
+	gs := opts.registered()
+	d, errs := declare(p, c, gs)
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	include, err := opts.include(c, d)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid filter for package %s", p.PkgPath)
+	}
+	explanations := make([]Explanation, 0, len(names))
+	for _, n := range names {
+		obj, ok := p.Types.Scope().Lookup(n).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		e := Explanation{Type: n, Position: p.Fset.Position(obj.Pos()), Excluded: !include.Match(obj)}
+		for _, g := range gs {
+			if !opts.runs(g) {
+				continue
+			}
+			ge := explainGenerator(p, c, d, opts, g, obj)
+			if e.Excluded {
+				ge.Methods = nil
+			}
+			e.Generators = append(e.Generators, ge)
+		}
+		explanations = append(explanations, e)
+	}
+	return explanations, nil
+}
+
+func explainGenerator(p *packages.Package, c comments.Comments, d declarations, opts *options, g Generator, o types.Object) GeneratorExplanation {
+	ge := GeneratorExplanation{
+		Generator: g.Name,
+		Matched:   d.Matcher(g, c).Match(o),
+		Disabled:  match.HasMarker(c, DisableMarker, "false")(o),
+	}
+	_, ge.Declared = d[o]
+	if s := match.Scope(c, o); g.Scope != "" && s != g.Scope && !ge.Declared {
+		ge.WrongScope = s
+	}
+	r, reacher := g.Matcher.(match.Reacher)
+	e, explainer := g.Matcher.(match.Explainer)
+	switch {
+	case ge.Declared && reacher:
+		ge.Clauses = r.Reachable(o)
+	case explainer:
+		ge.Clauses = e.Explain(o)
+	}
+	if !ge.Matched || ge.Disabled {
+		return ge
+	}
+
+	outside := method.DefinedOutside(p.Fset, opts.path(p, g.Output))
+	ms := g.Methods(g.Receiver, c)
+	for _, name := range slices.Sorted(maps.Keys(ms)) {
+		me := MethodExplanation{Method: name}
+		if outside(o, name) {
+			me.DefinedAt, _ = method.DefinedAt(p.Fset, o, name)
+		}
+		ge.Methods = append(ge.Methods, me)
+	}
+	return ge
+}
+
+// String returns a human readable explanation.
+func (e Explanation) String() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%s (%s)\n", e.Type, e.Position)
+	if e.Excluded {
+		fmt.Fprintf(b, "  excluded by filter; no methods are generated\n")
+	}
+	for _, g := range e.Generators {
+		switch {
+		case g.Matched && g.Disabled:
+			fmt.Fprintf(b, "  %s: matched, but disabled by +%s=false\n", g.Generator, DisableMarker)
+		case g.Matched && g.Declared:
+			fmt.Fprintf(b, "  %s: matched by +%s\n", g.Generator, KindMarker)
+		case g.Matched:
+			fmt.Fprintf(b, "  %s: matched\n", g.Generator)
+		case g.WrongScope != "":
+			fmt.Fprintf(b, "  %s: not matched, type is %s scoped\n", g.Generator, g.WrongScope)
+		default:
+			fmt.Fprintf(b, "  %s: not matched\n", g.Generator)
+		}
+		writeClauses(b, g.Clauses, "    ")
+		if len(g.Methods) == 0 {
+			continue
+		}
+		fmt.Fprintf(b, "    methods:\n")
+		for _, m := range g.Methods {
+			if m.Skipped() {
+				fmt.Fprintf(b, "      - %s: skipped, already defined at %s\n", m.Method, m.DefinedAt)
+				continue
+			}
+			fmt.Fprintf(b, "      + %s\n", m.Method)
+		}
+	}
+	return b.String()
+}
+
+func writeClauses(b *strings.Builder, r []ClauseResult, indent string) {
+	for _, c := range r {
+		mark := "✗"
+		if c.Matched {
+			mark = "✓"
+		}
+		fmt.Fprintf(b, "%s%s %s\n", indent, mark, c.Clause)
+		writeClauses(b, c.Fields, indent+"  ")
+	}
+}
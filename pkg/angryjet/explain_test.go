@@ -0,0 +1,207 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package angryjet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExplainPackage(t *testing.T) {
+	pkg := loadFixturePackageWith(t, map[string]string{"explain.go": `package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NoStatus struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec NamespacedResourceSpec
+}
+
+func (mg *NamespacedResource) GetCondition() {}
+`})
+
+	t.Run("NotMatched", func(t *testing.T) {
+		es, err := ExplainPackage(pkg, "NoStatus")
+		if err != nil {
+			t.Fatalf("ExplainPackage(...): %v", err)
+		}
+		if len(es) != 1 {
+			t.Fatalf("ExplainPackage(...): want 1 explanation, got %d", len(es))
+		}
+		ge := explanationFor(t, es[0], "managed-modern-core")
+		if ge.Matched {
+			t.Errorf("ExplainPackage(...): want managed-modern-core not to match NoStatus")
+		}
+		got := map[string]bool{}
+		for _, c := range ge.Clauses {
+			got[c.Clause] = c.Matched
+		}
+		want := map[string]bool{
+			"embedded k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta":   true,
+			"embedded k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta": true,
+			"field Spec":   true,
+			"field Status": false,
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ExplainPackage(...): -want, +got:\n%s", diff)
+		}
+		if !strings.Contains(es[0].String(), "✗ field Status") {
+			t.Errorf("String(): want failed Status clause, got:\n%s", es[0])
+		}
+	})
+
+	t.Run("SkippedMethod", func(t *testing.T) {
+		es, err := ExplainPackage(pkg, "NamespacedResource")
+		if err != nil {
+			t.Fatalf("ExplainPackage(...): %v", err)
+		}
+		ge := explanationFor(t, es[0], "managed-modern-core")
+		if !ge.Matched {
+			t.Fatalf("ExplainPackage(...): want managed-modern-core to match NamespacedResource")
+		}
+		skipped := []string{}
+		for _, m := range ge.Methods {
+			if m.Skipped() {
+				skipped = append(skipped, m.Method)
+				if !strings.HasSuffix(m.DefinedAt.Filename, "explain.go") {
+					t.Errorf("%s: want DefinedAt in explain.go, got %s", m.Method, m.DefinedAt)
+				}
+			}
+		}
+		if diff := cmp.Diff([]string{"GetCondition"}, skipped); diff != "" {
+			t.Errorf("ExplainPackage(...): skipped methods -want, +got:\n%s", diff)
+		}
+	})
+
+	t.Run("WrongScope", func(t *testing.T) {
+		es, err := ExplainPackage(pkg, "ClusterCoreProviderConfig")
+		if err != nil {
+			t.Fatalf("ExplainPackage(...): %v", err)
+		}
+		ge := explanationFor(t, es[0], "providerconfig-core")
+		if ge.Matched || ge.WrongScope != "Cluster" {
+			t.Errorf("ExplainPackage(...): want providerconfig-core not to match cluster scoped ClusterCoreProviderConfig, got %+v", ge)
+		}
+		if !strings.Contains(es[0].String(), "providerconfig-core: not matched, type is Cluster scoped") {
+			t.Errorf("String(): want scope explanation, got:\n%s", es[0])
+		}
+	})
+
+	t.Run("Excluded", func(t *testing.T) {
+		es, err := ExplainPackage(pkg, "NamespacedResource", WithFilter(`!name("^Namespaced")`))
+		if err != nil {
+			t.Fatalf("ExplainPackage(...): %v", err)
+		}
+		if !es[0].Excluded {
+			t.Errorf("ExplainPackage(...): want NamespacedResource to be excluded by filter")
+		}
+		if ge := explanationFor(t, es[0], "managed-modern-core"); !ge.Matched || len(ge.Methods) != 0 {
+			t.Errorf("ExplainPackage(...): want managed-modern-core to match without methods, got %+v", ge)
+		}
+		if !strings.Contains(es[0].String(), "excluded by filter") {
+			t.Errorf("String(): want filter explanation, got:\n%s", es[0])
+		}
+	})
+
+	t.Run("UnknownType", func(t *testing.T) {
+		if _, err := ExplainPackage(pkg, "Nope"); err == nil {
+			t.Errorf("ExplainPackage(...): want error for unknown type")
+		}
+	})
+}
+
+func TestExplainPackageFilterMatches(t *testing.T) {
+	pkg := loadFixturePackageWith(t, map[string]string{"declared.go": `package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +crossplane:generate:kind=clusterproviderconfig
+type DeclaredProviderConfig struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	Spec   CoreProviderConfigSpec
+	Status CoreProviderConfigStatus
+}
+`})
+
+	cases := map[string]struct {
+		reason   string
+		filter   string
+		typeName string
+		excluded bool
+	}{
+		"ScopedGeneratorMatchesItsScope": {
+			reason:   "A scoped generator should match a type of its scope.",
+			filter:   `matches("clusterproviderconfig-core")`,
+			typeName: "ClusterCoreProviderConfig",
+			excluded: false,
+		},
+		"ScopedGeneratorIgnoresOtherScope": {
+			reason:   "A scoped generator should not match a type of another scope.",
+			filter:   `matches("clusterproviderconfig-core")`,
+			typeName: "CoreProviderConfig",
+			excluded: true,
+		},
+		"NamespacedGeneratorIgnoresClusterScope": {
+			reason:   "A namespaced generator should not match a cluster scoped type.",
+			filter:   `matches("providerconfig-core")`,
+			typeName: "ClusterCoreProviderConfig",
+			excluded: true,
+		},
+		"DeclaredKind": {
+			reason:   "A generator of the kind a type declares should match it, regardless of its scope.",
+			filter:   `matches("clusterproviderconfig-core")`,
+			typeName: "DeclaredProviderConfig",
+			excluded: false,
+		},
+		"OtherKindThanDeclared": {
+			reason:   "A generator of another kind than a type declares should not match it.",
+			filter:   `matches("providerconfig-core")`,
+			typeName: "DeclaredProviderConfig",
+			excluded: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			es, err := ExplainPackage(pkg, tc.typeName, WithFilter(tc.filter))
+			if err != nil {
+				t.Fatalf("\n%s\nExplainPackage(...): %v", tc.reason, err)
+			}
+			if es[0].Excluded != tc.excluded {
+				t.Errorf("\n%s\nExplainPackage(...): want Excluded %t, got %t", tc.reason, tc.excluded, es[0].Excluded)
+			}
+		})
+	}
+}
+
+func explanationFor(t *testing.T, e Explanation, generator string) GeneratorExplanation {
+	t.Helper()
+	for _, ge := range e.Generators {
+		if ge.Generator == generator {
+			return ge
+		}
+	}
+	t.Fatalf("%s: no explanation for generator %s", e.Type, generator)
+	return GeneratorExplanation{}
+}
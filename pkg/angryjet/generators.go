@@ -0,0 +1,588 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package angryjet
+
+import (
+	"github.com/dave/jennifer/jen"
+
+	"github.com/crossplane/crossplane-tools/internal/comments"
+	"github.com/crossplane/crossplane-tools/internal/fields"
+	"github.com/crossplane/crossplane-tools/internal/match"
+	"github.com/crossplane/crossplane-tools/internal/method"
+	"github.com/crossplane/crossplane-tools/internal/types"
+)
+
+// Kinds of resource that angryjet generates methods for.
+const (
+	KindManaged                 = "managed"
+	KindManagedList             = "managedlist"
+	KindProviderConfig          = "providerconfig"
+	KindClusterProviderConfig   = "clusterproviderconfig"
+	KindProviderConfigUsage     = "providerconfigusage"
+	KindProviderConfigUsageList = "providerconfigusagelist"
+	KindComposite               = "composite"
+	KindClaim                   = "claim"
+	KindPackage                 = "package"
+	KindPackageRevision         = "packagerevision"
+)
+
+// Flavors of API types that angryjet generates methods for. Legacy resources
+// are cluster-scoped and modern resources are namespaced. Core resources use
+// the crossplane/apis/v2/core/v2 module rather than crossplane-runtime.
+const (
+	FlavorLegacy     = "legacy"
+	FlavorModern     = "modern"
+	FlavorLegacyCore = "legacy-core"
+	FlavorModernCore = "modern-core"
+
+	// FlavorCore is used by kinds whose method set does not vary by scope.
+	FlavorCore = "core"
+)
+
+// Outputs of generators. Each output is written to its own file.
+const (
+	OutputManaged     = "managed"
+	OutputManagedList = "managedlist"
+	OutputResolvers   = "resolvers"
+	OutputPC          = "pc"
+	OutputPCU         = "pcu"
+	OutputPCUList     = "pculist"
+	OutputComposite   = "composite"
+	OutputPackage     = "package"
+)
+
+// DefaultGenerators returns a registry of the method sets angryjet generates
+// by default.
+func DefaultGenerators() *Registry {
+	return defaultGenerators(nil)
+}
+
+// defaultGenerators returns the default generators. Their ResolveReferences
+// methods resolve references in the supplied roots of managed resources that
+// don't specify their own.
+func defaultGenerators(referenceRoots []string) *Registry {
+	return NewRegistry(
+		Generator{
+			Name:          KindManaged + "-" + FlavorLegacy,
+			Kind:          KindManaged,
+			Flavor:        FlavorLegacy,
+			Output:        OutputManaged,
+			Receiver:      "mg",
+			ImportAliases: map[string]string{CoreImport: CoreAlias, RuntimeImport: RuntimeAlias},
+			Matcher:       match.ManagedLegacy(),
+			Methods:       managedLegacyMethods(RuntimeImport),
+		},
+		Generator{
+			Name:          KindManaged + "-" + FlavorModern,
+			Kind:          KindManaged,
+			Flavor:        FlavorModern,
+			Output:        OutputManaged,
+			Receiver:      "mg",
+			ImportAliases: map[string]string{CoreImport: CoreAlias, RuntimeImport: RuntimeAlias},
+			Matcher:       match.ManagedModern(),
+			Methods:       managedModernMethods(RuntimeImport),
+		},
+		Generator{
+			Name:          KindManaged + "-" + FlavorLegacyCore,
+			Kind:          KindManaged,
+			Flavor:        FlavorLegacyCore,
+			Output:        OutputManaged,
+			Receiver:      "mg",
+			ImportAliases: map[string]string{CoreImport: CoreAlias, RuntimeV2Import: RuntimeV2Alias},
+			Matcher:       match.ManagedLegacyCore(),
+			Methods:       managedLegacyMethods(RuntimeV2Import),
+		},
+		Generator{
+			Name:          KindManaged + "-" + FlavorModernCore,
+			Kind:          KindManaged,
+			Flavor:        FlavorModernCore,
+			Output:        OutputManaged,
+			Receiver:      "mg",
+			ImportAliases: map[string]string{CoreImport: CoreAlias, RuntimeV2Import: RuntimeV2Alias},
+			Matcher:       match.ManagedModernCore(),
+			Methods:       managedModernMethods(RuntimeV2Import),
+		},
+		Generator{
+			Name:          KindManagedList + "-" + FlavorLegacy,
+			Kind:          KindManagedList,
+			Flavor:        FlavorLegacy,
+			Output:        OutputManagedList,
+			Receiver:      "l",
+			ImportAliases: map[string]string{ResourceImport: ResourceAlias},
+			Matcher:       match.ManagedListLegacy(),
+			Methods:       managedListMethods,
+		},
+		Generator{
+			Name:          KindManagedList + "-" + FlavorModern,
+			Kind:          KindManagedList,
+			Flavor:        FlavorModern,
+			Output:        OutputManagedList,
+			Receiver:      "l",
+			ImportAliases: map[string]string{ResourceImport: ResourceAlias},
+			Matcher:       match.ManagedListModern(),
+			Methods:       managedListMethods,
+		},
+		Generator{
+			Name:          KindManagedList + "-" + FlavorLegacyCore,
+			Kind:          KindManagedList,
+			Flavor:        FlavorLegacyCore,
+			Output:        OutputManagedList,
+			Receiver:      "l",
+			ImportAliases: map[string]string{ResourceImport: ResourceAlias},
+			Matcher:       match.ManagedListLegacyCore(),
+			Methods:       managedListMethods,
+		},
+		Generator{
+			Name:          KindManagedList + "-" + FlavorModernCore,
+			Kind:          KindManagedList,
+			Flavor:        FlavorModernCore,
+			Output:        OutputManagedList,
+			Receiver:      "l",
+			ImportAliases: map[string]string{ResourceImport: ResourceAlias},
+			Matcher:       match.ManagedListModernCore(),
+			Methods:       managedListMethods,
+		},
+		Generator{
+			Name:          KindProviderConfig + "-" + FlavorLegacy,
+			Kind:          KindProviderConfig,
+			Flavor:        FlavorLegacy,
+			Output:        OutputPC,
+			Receiver:      "p",
+			ImportAliases: map[string]string{RuntimeImport: RuntimeAlias},
+			Matcher:       match.ProviderConfig(),
+			Methods:       providerConfigMethods(RuntimeImport),
+		},
+		// Core providers may ship both a namespaced ProviderConfig and a
+		// ClusterProviderConfig. Their API types are the same shape, so they're
+		// told apart by scope.
+		Generator{
+			Name:          KindProviderConfig + "-" + FlavorCore,
+			Kind:          KindProviderConfig,
+			Flavor:        FlavorCore,
+			Scope:         match.ScopeNamespaced,
+			Output:        OutputPC,
+			Receiver:      "p",
+			ImportAliases: map[string]string{RuntimeV2Import: RuntimeV2Alias},
+			Matcher:       match.ProviderConfigCore(),
+			Methods:       providerConfigMethods(RuntimeV2Import),
+		},
+		Generator{
+			Name:          KindClusterProviderConfig + "-" + FlavorCore,
+			Kind:          KindClusterProviderConfig,
+			Flavor:        FlavorCore,
+			Scope:         match.ScopeCluster,
+			Output:        OutputPC,
+			Receiver:      "p",
+			ImportAliases: map[string]string{RuntimeV2Import: RuntimeV2Alias},
+			Matcher:       match.ProviderConfigCore(),
+			Methods:       clusterProviderConfigMethods(RuntimeV2Import),
+		},
+		Generator{
+			Name:          KindProviderConfigUsage + "-" + FlavorLegacy,
+			Kind:          KindProviderConfigUsage,
+			Flavor:        FlavorLegacy,
+			Output:        OutputPCU,
+			Receiver:      "p",
+			ImportAliases: map[string]string{RuntimeImport: RuntimeAlias},
+			Matcher:       match.ProviderConfigUsageLegacy(),
+			Methods:       providerConfigUsageLegacyMethods(RuntimeImport),
+		},
+		Generator{
+			Name:          KindProviderConfigUsage + "-" + FlavorModern,
+			Kind:          KindProviderConfigUsage,
+			Flavor:        FlavorModern,
+			Output:        OutputPCU,
+			Receiver:      "p",
+			ImportAliases: map[string]string{RuntimeImport: RuntimeAlias},
+			Matcher:       match.ProviderConfigUsageModern(),
+			Methods:       providerConfigUsageModernMethods(RuntimeImport),
+		},
+		Generator{
+			Name:          KindProviderConfigUsage + "-" + FlavorLegacyCore,
+			Kind:          KindProviderConfigUsage,
+			Flavor:        FlavorLegacyCore,
+			Output:        OutputPCU,
+			Receiver:      "p",
+			ImportAliases: map[string]string{RuntimeV2Import: RuntimeV2Alias},
+			Matcher:       match.ProviderConfigUsageLegacyCore(),
+			Methods:       providerConfigUsageLegacyMethods(RuntimeV2Import),
+		},
+		Generator{
+			Name:          KindProviderConfigUsage + "-" + FlavorModernCore,
+			Kind:          KindProviderConfigUsage,
+			Flavor:        FlavorModernCore,
+			Output:        OutputPCU,
+			Receiver:      "p",
+			ImportAliases: map[string]string{RuntimeV2Import: RuntimeV2Alias},
+			Matcher:       match.ProviderConfigUsageModernCore(),
+			Methods:       providerConfigUsageModernMethods(RuntimeV2Import),
+		},
+		Generator{
+			Name:          KindProviderConfigUsageList + "-" + FlavorLegacy,
+			Kind:          KindProviderConfigUsageList,
+			Flavor:        FlavorLegacy,
+			Output:        OutputPCUList,
+			Receiver:      "p",
+			ImportAliases: map[string]string{ResourceImport: ResourceAlias},
+			Matcher:       match.ProviderConfigUsageListLegacy(),
+			Methods:       providerConfigUsageListMethods,
+		},
+		Generator{
+			Name:          KindProviderConfigUsageList + "-" + FlavorModern,
+			Kind:          KindProviderConfigUsageList,
+			Flavor:        FlavorModern,
+			Output:        OutputPCUList,
+			Receiver:      "p",
+			ImportAliases: map[string]string{ResourceImport: ResourceAlias},
+			Matcher:       match.ProviderConfigUsageListModern(),
+			Methods:       providerConfigUsageListMethods,
+		},
+		Generator{
+			Name:          KindProviderConfigUsageList + "-" + FlavorLegacyCore,
+			Kind:          KindProviderConfigUsageList,
+			Flavor:        FlavorLegacyCore,
+			Output:        OutputPCUList,
+			Receiver:      "p",
+			ImportAliases: map[string]string{ResourceImport: ResourceAlias},
+			Matcher:       match.ProviderConfigUsageListLegacyCore(),
+			Methods:       providerConfigUsageListMethods,
+		},
+		Generator{
+			Name:          KindProviderConfigUsageList + "-" + FlavorModernCore,
+			Kind:          KindProviderConfigUsageList,
+			Flavor:        FlavorModernCore,
+			Output:        OutputPCUList,
+			Receiver:      "p",
+			ImportAliases: map[string]string{ResourceImport: ResourceAlias},
+			Matcher:       match.ProviderConfigUsageListModernCore(),
+			Methods:       providerConfigUsageListMethods,
+		},
+		Generator{
+			Name:          "references-" + FlavorLegacy,
+			Kind:          KindManaged,
+			Flavor:        FlavorLegacy,
+			Output:        OutputResolvers,
+			Receiver:      "mg",
+			ImportAliases: map[string]string{ClientImport: ClientAlias, ReferenceImport: ReferenceAlias},
+			Matcher:       match.ManagedLegacy(),
+			Methods:       referencesMethods(method.NewResolveReferences, referenceRoots),
+		},
+		Generator{
+			Name:          "references-" + FlavorModern,
+			Kind:          KindManaged,
+			Flavor:        FlavorModern,
+			Output:        OutputResolvers,
+			Receiver:      "mg",
+			ImportAliases: map[string]string{ClientImport: ClientAlias, ReferenceImport: ReferenceAlias},
+			Matcher:       match.ManagedModern(),
+			Methods:       referencesMethods(method.NewResolveReferencesV2, referenceRoots),
+		},
+		Generator{
+			Name:          "references-" + FlavorLegacyCore,
+			Kind:          KindManaged,
+			Flavor:        FlavorLegacyCore,
+			Output:        OutputResolvers,
+			Receiver:      "mg",
+			ImportAliases: map[string]string{ClientImport: ClientAlias, ReferenceImport: ReferenceAlias},
+			Matcher:       match.ManagedLegacyCore(),
+			Methods:       referencesMethods(method.NewResolveReferences, referenceRoots),
+		},
+		Generator{
+			Name:          "references-" + FlavorModernCore,
+			Kind:          KindManaged,
+			Flavor:        FlavorModernCore,
+			Output:        OutputResolvers,
+			Receiver:      "mg",
+			ImportAliases: map[string]string{ClientImport: ClientAlias, ReferenceImport: ReferenceAlias},
+			Matcher:       match.ManagedModernCore(),
+			Methods:       referencesMethods(method.NewResolveReferencesV2, referenceRoots),
+		},
+		Generator{
+			Name:          KindComposite + "-" + FlavorCore,
+			Kind:          KindComposite,
+			Flavor:        FlavorCore,
+			Output:        OutputComposite,
+			Receiver:      "xr",
+			ImportAliases: map[string]string{CoreImport: CoreAlias, MetaImport: MetaAlias, RuntimeV2Import: RuntimeV2Alias, ReferenceImport: ReferenceAlias},
+			Matcher:       match.CompositeCore(),
+			Methods:       compositeMethods(RuntimeV2Import),
+		},
+		Generator{
+			Name:          KindClaim + "-" + FlavorCore,
+			Kind:          KindClaim,
+			Flavor:        FlavorCore,
+			Output:        OutputComposite,
+			Receiver:      "cm",
+			ImportAliases: map[string]string{CoreImport: CoreAlias, MetaImport: MetaAlias, RuntimeV2Import: RuntimeV2Alias},
+			Matcher:       match.CompositeClaimCore(),
+			Methods:       claimMethods(RuntimeV2Import),
+		},
+		Generator{
+			Name:          KindPackage + "-" + FlavorCore,
+			Kind:          KindPackage,
+			Flavor:        FlavorCore,
+			Output:        OutputPackage,
+			Receiver:      "p",
+			ImportAliases: map[string]string{CoreImport: CoreAlias, PackageImport: PackageAlias, RuntimeV2Import: RuntimeV2Alias},
+			Matcher:       match.Package(),
+			Methods:       packageMethods(RuntimeV2Import),
+		},
+		Generator{
+			Name:          KindPackageRevision + "-" + FlavorCore,
+			Kind:          KindPackageRevision,
+			Flavor:        FlavorCore,
+			Output:        OutputPackage,
+			Receiver:      "pr",
+			ImportAliases: map[string]string{CoreImport: CoreAlias, PackageImport: PackageAlias, RuntimeV2Import: RuntimeV2Alias},
+			Matcher:       match.PackageRevision(),
+			Methods:       packageRevisionMethods(RuntimeV2Import),
+		},
+	)
+}
+
+// managedLegacyMethods returns the resource.Managed method set for
+// cluster-scoped (legacy) managed resources.
+func managedLegacyMethods(runtime string) func(string, comments.Comments) method.Set {
+	return func(receiver string, _ comments.Comments) method.Set {
+		return method.Set{
+			"SetConditions":                       method.NewSetConditions(receiver, runtime),
+			"GetCondition":                        method.NewGetCondition(receiver, runtime),
+			"GetProviderConfigReference":          method.NewGetProviderConfigReference(receiver, runtime),
+			"SetProviderConfigReference":          method.NewSetProviderConfigReference(receiver, runtime),
+			"SetWriteConnectionSecretToReference": method.NewSetWriteConnectionSecretToReference(receiver, runtime),
+			"GetWriteConnectionSecretToReference": method.NewGetWriteConnectionSecretToReference(receiver, runtime),
+			"SetManagementPolicies":               method.NewSetManagementPolicies(receiver, runtime),
+			"GetManagementPolicies":               method.NewGetManagementPolicies(receiver, runtime),
+			"SetDeletionPolicy":                   method.NewSetDeletionPolicy(receiver, runtime),
+			"GetDeletionPolicy":                   method.NewGetDeletionPolicy(receiver, runtime),
+		}
+	}
+}
+
+// managedModernMethods returns the resource.Managed method set for namespaced
+// (modern) managed resources.
+func managedModernMethods(runtime string) func(string, comments.Comments) method.Set {
+	return func(receiver string, _ comments.Comments) method.Set {
+		return method.Set{
+			"SetConditions":                       method.NewSetConditions(receiver, runtime),
+			"GetCondition":                        method.NewGetCondition(receiver, runtime),
+			"GetProviderConfigReference":          method.NewGetTypedProviderConfigReference(receiver, runtime),
+			"SetProviderConfigReference":          method.NewSetTypedProviderConfigReference(receiver, runtime),
+			"SetWriteConnectionSecretToReference": method.NewLocalSetWriteConnectionSecretToReference(receiver, runtime),
+			"GetWriteConnectionSecretToReference": method.NewLocalGetWriteConnectionSecretToReference(receiver, runtime),
+			"SetManagementPolicies":               method.NewSetManagementPolicies(receiver, runtime),
+			"GetManagementPolicies":               method.NewGetManagementPolicies(receiver, runtime),
+		}
+	}
+}
+
+// managedListMethods returns the resource.ManagedList method set.
+func managedListMethods(receiver string, _ comments.Comments) method.Set {
+	return method.Set{
+		"GetItems": method.NewManagedGetItems(receiver, ResourceImport),
+	}
+}
+
+// providerConfigMethods returns the resource.ProviderConfig method set.
+func providerConfigMethods(runtime string) func(string, comments.Comments) method.Set {
+	return func(receiver string, _ comments.Comments) method.Set {
+		return method.Set{
+			"SetUsers":      method.NewSetUsers(receiver),
+			"GetUsers":      method.NewGetUsers(receiver),
+			"SetConditions": method.NewSetConditions(receiver, runtime),
+			"GetCondition":  method.NewGetCondition(receiver, runtime),
+		}
+	}
+}
+
+// clusterProviderConfigMethods returns the resource.ProviderConfig method set
+// for cluster scoped ProviderConfigs. Those that set the
+// ReferenceConversionMarker also get functions that convert references between
+// them and their namespaced counterpart.
+func clusterProviderConfigMethods(runtime string) func(string, comments.Comments) method.Set {
+	return func(receiver string, c comments.Comments) method.Set {
+		ms := providerConfigMethods(runtime)(receiver, c)
+		ms["ConvertProviderConfigReferences"] = when(match.HasMarker(c, ReferenceConversionMarker, "true"), method.NewConvertProviderConfigReferences(runtime))
+		return ms
+	}
+}
+
+// providerConfigUsageLegacyMethods returns the resource.ProviderConfigUsage
+// method set for usages that embed a non-typed ProviderConfigUsage.
+func providerConfigUsageLegacyMethods(runtime string) func(string, comments.Comments) method.Set {
+	return func(receiver string, _ comments.Comments) method.Set {
+		return method.Set{
+			"SetProviderConfigReference": method.NewSetRootProviderConfigReference(receiver, runtime),
+			"GetProviderConfigReference": method.NewGetRootProviderConfigReference(receiver, runtime),
+			"SetResourceReference":       method.NewSetRootResourceReference(receiver, runtime),
+			"GetResourceReference":       method.NewGetRootResourceReference(receiver, runtime),
+		}
+	}
+}
+
+// providerConfigUsageModernMethods returns the resource.ProviderConfigUsage
+// method set for usages that embed a TypedProviderConfigUsage.
+func providerConfigUsageModernMethods(runtime string) func(string, comments.Comments) method.Set {
+	return func(receiver string, _ comments.Comments) method.Set {
+		return method.Set{
+			"SetProviderConfigReference": method.NewSetRootProviderConfigTypedReference(receiver, runtime),
+			"GetProviderConfigReference": method.NewGetRootProviderConfigTypedReference(receiver, runtime),
+			"SetResourceReference":       method.NewSetRootResourceReference(receiver, runtime),
+			"GetResourceReference":       method.NewGetRootResourceReference(receiver, runtime),
+		}
+	}
+}
+
+// providerConfigUsageListMethods returns the resource.ProviderConfigUsageList
+// method set.
+func providerConfigUsageListMethods(receiver string, _ comments.Comments) method.Set {
+	return method.Set{
+		"GetItems": method.NewProviderConfigUsageGetItems(receiver, ResourceImport),
+	}
+}
+
+// compositeMethods returns the resource.Composite method set.
+func compositeMethods(runtime string) func(string, comments.Comments) method.Set {
+	return func(receiver string, _ comments.Comments) method.Set {
+		return method.Set{
+			"SetConditions":                         method.NewSetConditions(receiver, runtime),
+			"GetCondition":                          method.NewGetCondition(receiver, runtime),
+			"SetCompositionSelector":                method.NewSetCompositionSelector(receiver, MetaImport),
+			"GetCompositionSelector":                method.NewGetCompositionSelector(receiver, MetaImport),
+			"SetCompositionReference":               method.NewSetCompositionReference(receiver, CoreImport),
+			"GetCompositionReference":               method.NewGetCompositionReference(receiver, CoreImport),
+			"SetCompositionRevisionReference":       method.NewSetCompositionRevisionReference(receiver, CoreImport),
+			"GetCompositionRevisionReference":       method.NewGetCompositionRevisionReference(receiver, CoreImport),
+			"SetCompositionUpdatePolicy":            method.NewSetCompositionUpdatePolicy(receiver, runtime),
+			"GetCompositionUpdatePolicy":            method.NewGetCompositionUpdatePolicy(receiver, runtime),
+			"SetResourceReferences":                 method.NewSetResourceReferences(receiver, CoreImport),
+			"GetResourceReferences":                 method.NewGetResourceReferences(receiver, CoreImport),
+			"SetClaimReference":                     method.NewSetClaimReference(receiver, ReferenceImport),
+			"GetClaimReference":                     method.NewGetClaimReference(receiver, ReferenceImport),
+			"SetWriteConnectionSecretToReference":   method.NewSetWriteConnectionSecretToReference(receiver, runtime),
+			"GetWriteConnectionSecretToReference":   method.NewGetWriteConnectionSecretToReference(receiver, runtime),
+			"SetConnectionDetailsLastPublishedTime": method.NewSetConnectionDetailsLastPublishedTime(receiver, MetaImport),
+			"GetConnectionDetailsLastPublishedTime": method.NewGetConnectionDetailsLastPublishedTime(receiver, MetaImport),
+		}
+	}
+}
+
+// claimMethods returns the resource.CompositeClaim method set.
+func claimMethods(runtime string) func(string, comments.Comments) method.Set {
+	return func(receiver string, _ comments.Comments) method.Set {
+		return method.Set{
+			"SetConditions":                         method.NewSetConditions(receiver, runtime),
+			"GetCondition":                          method.NewGetCondition(receiver, runtime),
+			"SetCompositionSelector":                method.NewSetCompositionSelector(receiver, MetaImport),
+			"GetCompositionSelector":                method.NewGetCompositionSelector(receiver, MetaImport),
+			"SetCompositionReference":               method.NewSetCompositionReference(receiver, CoreImport),
+			"GetCompositionReference":               method.NewGetCompositionReference(receiver, CoreImport),
+			"SetCompositionRevisionReference":       method.NewSetCompositionRevisionReference(receiver, CoreImport),
+			"GetCompositionRevisionReference":       method.NewGetCompositionRevisionReference(receiver, CoreImport),
+			"SetCompositionUpdatePolicy":            method.NewSetCompositionUpdatePolicy(receiver, runtime),
+			"GetCompositionUpdatePolicy":            method.NewGetCompositionUpdatePolicy(receiver, runtime),
+			"SetCompositeDeletePolicy":              method.NewSetCompositeDeletePolicy(receiver, runtime),
+			"GetCompositeDeletePolicy":              method.NewGetCompositeDeletePolicy(receiver, runtime),
+			"SetResourceReference":                  method.NewSetResourceReference(receiver, CoreImport),
+			"GetResourceReference":                  method.NewGetResourceReference(receiver, CoreImport),
+			"SetWriteConnectionSecretToReference":   method.NewLocalSetWriteConnectionSecretToReference(receiver, runtime),
+			"GetWriteConnectionSecretToReference":   method.NewLocalGetWriteConnectionSecretToReference(receiver, runtime),
+			"SetConnectionDetailsLastPublishedTime": method.NewSetConnectionDetailsLastPublishedTime(receiver, MetaImport),
+			"GetConnectionDetailsLastPublishedTime": method.NewGetConnectionDetailsLastPublishedTime(receiver, MetaImport),
+		}
+	}
+}
+
+// packageMethods returns the method set of a Crossplane package, such as a
+// Provider.
+func packageMethods(runtime string) func(string, comments.Comments) method.Set {
+	return func(receiver string, _ comments.Comments) method.Set {
+		ms := method.Set{
+			"SetConditions": method.NewSetConditions(receiver, runtime),
+			"GetCondition":  method.NewGetCondition(receiver, runtime),
+		}
+		addFields(ms, receiver, packageSpecFields())
+		addFields(ms, receiver, []field{
+			{name: "ActivationPolicy", typ: jen.Op("*").Qual(PackageImport, "RevisionActivationPolicy"), path: []string{fields.NameSpec, "RevisionActivationPolicy"}},
+			{name: "RevisionHistoryLimit", typ: jen.Op("*").Int64(), path: []string{fields.NameSpec, "RevisionHistoryLimit"}},
+			{name: "CurrentRevision", typ: jen.String(), path: []string{fields.NameStatus, "CurrentRevision"}},
+			{name: "CurrentIdentifier", typ: jen.String(), path: []string{fields.NameStatus, "CurrentIdentifier"}},
+		})
+		return ms
+	}
+}
+
+// packageRevisionMethods returns the method set of a Crossplane package
+// revision, such as a ProviderRevision.
+func packageRevisionMethods(runtime string) func(string, comments.Comments) method.Set {
+	return func(receiver string, _ comments.Comments) method.Set {
+		ms := method.Set{
+			"SetConditions":       method.NewSetConditions(receiver, runtime),
+			"GetCondition":        method.NewGetCondition(receiver, runtime),
+			"SetDependencyStatus": method.NewSetDependencyStatus(receiver),
+			"GetDependencyStatus": method.NewGetDependencyStatus(receiver),
+		}
+		addFields(ms, receiver, packageSpecFields())
+		addFields(ms, receiver, []field{
+			{name: "DesiredState", typ: jen.Qual(PackageImport, "PackageRevisionDesiredState"), path: []string{fields.NameSpec, "DesiredState"}},
+			{name: "Revision", typ: jen.Int64(), path: []string{fields.NameSpec, "Revision"}},
+			{name: "Objects", typ: jen.Index().Qual(runtime, "TypedReference"), path: []string{fields.NameStatus, "ObjectRefs"}},
+		})
+		return ms
+	}
+}
+
+// A field for which a getter and setter are generated.
+type field struct {
+	name string
+	typ  jen.Code
+	path []string
+}
+
+// packageSpecFields returns the fields packages and package revisions share.
+func packageSpecFields() []field {
+	return []field{
+		{name: "Source", typ: jen.String(), path: []string{fields.NameSpec, "Package"}},
+		{name: "PackagePullSecrets", typ: jen.Index().Qual(CoreImport, "LocalObjectReference"), path: []string{fields.NameSpec, "PackagePullSecrets"}},
+		{name: "PackagePullPolicy", typ: jen.Op("*").Qual(CoreImport, "PullPolicy"), path: []string{fields.NameSpec, "PackagePullPolicy"}},
+		{name: "IgnoreCrossplaneConstraints", typ: jen.Op("*").Bool(), path: []string{fields.NameSpec, "IgnoreCrossplaneConstraints"}},
+		{name: "SkipDependencyResolution", typ: jen.Op("*").Bool(), path: []string{fields.NameSpec, "SkipDependencyResolution"}},
+		{name: "CommonLabels", typ: jen.Map(jen.String()).String(), path: []string{fields.NameSpec, "CommonLabels"}},
+	}
+}
+
+// addFields adds a getter and setter for each of the supplied fields to the
+// supplied method set.
+func addFields(ms method.Set, receiver string, fs []field) {
+	for _, f := range fs {
+		ms["Set"+f.name] = method.NewFieldSetter(receiver, f.name, f.typ, f.path...)
+		ms["Get"+f.name] = method.NewFieldGetter(receiver, f.name, f.typ, f.path...)
+	}
+}
+
+// referencesMethods returns a method set containing the ResolveReferences
+// method produced by the supplied constructor, resolving references in the
+// supplied default roots.
+func referencesMethods(fn func(*types.Traverser, string, string, string, ...method.ResolveReferencesOption) method.New, roots []string) func(string, comments.Comments) method.Set {
+	return func(receiver string, c comments.Comments) method.Set {
+		return method.Set{
+			"ResolveReferences": resolving(c, roots, func(t *types.Traverser, o ...method.ResolveReferencesOption) method.New {
+				return fn(t, receiver, ClientImport, ReferenceImport, o...)
+			}),
+		}
+	}
+}
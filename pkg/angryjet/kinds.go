@@ -0,0 +1,166 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package angryjet
+
+import (
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/crossplane/crossplane-tools/internal/comments"
+	"github.com/crossplane/crossplane-tools/internal/diagnostic"
+	"github.com/crossplane/crossplane-tools/internal/match"
+)
+
+// Declarations record the generators that match each type that declares its
+// kind using the KindMarker, by generator name.
+type declarations map[types.Object]map[string]bool
+
+// Matcher returns a matcher for the supplied generator. Types that declare
+// their kind are matched by the generators of that kind and flavor, regardless
+// of the generator's matcher and scope. Other types are matched by its matcher
+// if they have its scope. Comment markers are read from the supplied Comments.
+func (d declarations) Matcher(g Generator, c comments.Comments) match.Object {
+	return func(o types.Object) bool {
+		if names, ok := d[o]; ok {
+			return names[g.Name]
+		}
+		if g.Scope != "" && !match.InScope(c, g.Scope)(o) {
+			return false
+		}
+		return g.Matcher.Match(o)
+	}
+}
+
+// declare returns the generators that match each type of the supplied package
+// that declares its kind. It returns an error for each type whose declared
+// kind and flavor don't correspond to a generator, or whose fields the
+// generator requires aren't reachable.
+func declare(p *packages.Package, c comments.Comments, gs []Generator) (declarations, Errors) {
+	d := declarations{}
+	errs := Errors{}
+	for _, n := range p.Types.Scope().Names() {
+		o, ok := p.Types.Scope().Lookup(n).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		kinds, flavors := markerValues(c, o, KindMarker), markerValues(c, o, FlavorMarker)
+		if len(kinds) == 0 {
+			if len(flavors) > 0 {
+				errs = errs.Append(diagnostic.Errorf(p.Fset.Position(o.Pos()), "type %s declares a flavor using +%s, but not a kind using +%s", n, FlavorMarker, KindMarker))
+			}
+			continue
+		}
+
+		// A type that declares its kind is never matched structurally, even
+		// if its declaration is invalid.
+		d[o] = map[string]bool{}
+		gs, err := declared(o, kinds[0], flavors, gs)
+		if err != nil {
+			errs = errs.Append(diagnostic.At(p.Fset.Position(o.Pos()), err))
+			continue
+		}
+		for _, g := range gs {
+			d[o][g.Name] = true
+		}
+	}
+	return d, errs
+}
+
+// declared returns the generators of the supplied kind and flavor that match
+// the supplied object. If no flavor is supplied the object must match exactly
+// one flavor.
+func declared(o types.Object, kind string, flavors []string, gs []Generator) ([]Generator, error) {
+	byFlavor := map[string][]Generator{}
+	order := make([]string, 0)
+	for _, g := range gs {
+		if g.Kind != kind {
+			continue
+		}
+		if _, ok := byFlavor[g.Flavor]; !ok {
+			order = append(order, g.Flavor)
+		}
+		byFlavor[g.Flavor] = append(byFlavor[g.Flavor], g)
+	}
+	if len(order) == 0 {
+		return nil, errors.Errorf("type %s declares unknown kind %q", o.Name(), kind)
+	}
+
+	if len(flavors) > 0 {
+		f := flavors[0]
+		if _, ok := byFlavor[f]; !ok {
+			return nil, errors.Errorf("type %s declares kind %s, which has no flavor %q", o.Name(), kind, f)
+		}
+		if m := unreachable(o, byFlavor[f]); len(m) > 0 {
+			return nil, errors.Errorf("type %s declares kind %s and flavor %s, but does not have %s", o.Name(), kind, f, strings.Join(m, ", "))
+		}
+		return byFlavor[f], nil
+	}
+
+	ok := make([]string, 0)
+	closest, missing := "", []string(nil)
+	for _, f := range order {
+		m := unreachable(o, byFlavor[f])
+		if len(m) == 0 {
+			ok = append(ok, f)
+			continue
+		}
+		if missing == nil || len(m) < len(missing) {
+			closest, missing = f, m
+		}
+	}
+	switch len(ok) {
+	case 0:
+		return nil, errors.Errorf("type %s declares kind %s, but does not have the fields of any flavor; flavor %s is closest, but does not have %s", o.Name(), kind, closest, strings.Join(missing, ", "))
+	case 1:
+		return byFlavor[ok[0]], nil
+	default:
+		return nil, errors.Errorf("type %s declares kind %s, and has the fields of flavors %s; declare one using +%s", o.Name(), kind, strings.Join(ok, ", "), FlavorMarker)
+	}
+}
+
+// unreachable returns a description of each field the supplied generators
+// require that isn't reachable from the supplied object.
+func unreachable(o types.Object, gs []Generator) []string {
+	out := make([]string, 0)
+	for _, g := range gs {
+		var m []string
+		switch r := g.Matcher.(type) {
+		case match.Reacher:
+			m = match.Missing(r.Reachable(o))
+		default:
+			if !g.Matcher.Match(o) {
+				m = []string{"the fields required by generator " + g.Name}
+			}
+		}
+		for _, s := range m {
+			if !slices.Contains(out, s) {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// markerValues returns the values of the supplied marker for the supplied
+// object.
+func markerValues(c comments.Comments, o types.Object, k string) []string {
+	return append(comments.ParseMarkers(c.For(o))[k], comments.ParseMarkers(c.Before(o))[k]...)
+}
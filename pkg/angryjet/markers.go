@@ -0,0 +1,188 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package angryjet
+
+import (
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-tools/internal/comments"
+	"github.com/crossplane/crossplane-tools/internal/diagnostic"
+	"github.com/crossplane/crossplane-tools/internal/match"
+	"github.com/crossplane/crossplane-tools/internal/method"
+	xptypes "github.com/crossplane/crossplane-tools/internal/types"
+	"github.com/crossplane/crossplane-tools/internal/validate"
+)
+
+// MarkerPrefix is the prefix of the comment markers angryjet understands.
+// Markers with this prefix are validated before methods are generated.
+const MarkerPrefix = "crossplane:generate:"
+
+// Markers that declare the kind of resource a type is, and the flavor of API
+// types it uses. A type that declares its kind gets the methods of that kind
+// even if its shape doesn't match, as long as the fields the methods use are
+// reachable. The flavor may be omitted if only one flavor's fields are
+// reachable.
+const (
+	KindMarker   = MarkerPrefix + "kind"
+	FlavorMarker = MarkerPrefix + "flavor"
+)
+
+// ReferenceConversionMarker opts a ClusterProviderConfig in to functions that
+// convert references between it and its namespaced ProviderConfig.
+const ReferenceConversionMarker = MarkerPrefix + "referenceConversion"
+
+// ReferenceMaxDepthMarker sets the maximum number of nested types that are
+// searched for references when generating the ResolveReferences method of a
+// managed resource. See types.DefaultMaxDepth.
+const ReferenceMaxDepthMarker = MarkerPrefix + "reference:maxDepth"
+
+// ReferenceHelpersMarker opts a managed resource in to resolving the references
+// of each nested named struct type in a helper method, rather than inline in
+// its ResolveReferences method.
+const ReferenceHelpersMarker = MarkerPrefix + "reference:helpers"
+
+// Markers that restrict the fields of a managed resource whose references are
+// resolved, for example to Spec.ForProvider,Spec.InitProvider. References in
+// other fields, for example in Status, are ignored unless the outsideRoots
+// marker is set to reject, in which case generation fails.
+const (
+	ReferenceRootsMarker        = MarkerPrefix + "reference:roots"
+	ReferenceOutsideRootsMarker = MarkerPrefix + "reference:outsideRoots"
+)
+
+// A Severity determines how problems that don't prevent generation, such as
+// malformed markers, are reported.
+type Severity string
+
+// Severities.
+const (
+	// SeverityWarning problems are reported as warnings. See WithWarnings.
+	SeverityWarning Severity = "warning"
+
+	// SeverityError problems are reported as errors, and prevent methods
+	// being generated for the package they occur in.
+	SeverityError Severity = "error"
+)
+
+// markers returns the comment markers angryjet understands. The kinds and
+// flavors that may be declared are those of the supplied generators.
+func markers(gs []Generator) []validate.Marker {
+	kinds, flavors := make([]string, 0), make([]string, 0)
+	for _, g := range gs {
+		if g.Kind != "" && !slices.Contains(kinds, g.Kind) {
+			kinds = append(kinds, g.Kind)
+		}
+		if g.Flavor != "" && !slices.Contains(flavors, g.Flavor) {
+			flavors = append(flavors, g.Flavor)
+		}
+	}
+	return []validate.Marker{
+		{Key: DisableMarker, Value: validate.OneOf("true", "false")},
+		{Key: KindMarker, Value: validate.OneOf(kinds...)},
+		{Key: FlavorMarker, Value: validate.OneOf(flavors...)},
+		{Key: ReferenceConversionMarker, Value: validate.OneOf("true", "false")},
+		{Key: ReferenceMaxDepthMarker, Value: validate.PositiveInteger},
+		{Key: ReferenceHelpersMarker, Value: validate.OneOf("true", "false")},
+		{Key: ReferenceRootsMarker, Value: validate.FieldPaths},
+		{Key: ReferenceOutsideRootsMarker, Value: validate.OneOf("ignore", "reject")},
+		{Key: method.ReferenceTypeMarker},
+		{Key: method.ReferenceExtractorMarker, Value: method.ValidateExtractor},
+		{Key: method.ReferenceReferenceFieldNameMarker, Value: validate.Identifier},
+		{Key: method.ReferenceSelectorFieldNameMarker, Value: validate.Identifier},
+	}
+}
+
+// referenceRoots returns the roots of the supplied object's references, either
+// from its reference:roots marker or from the supplied default roots. It
+// returns an error if the marker specifies a root the object doesn't have.
+// Default roots the object doesn't have are ignored.
+func referenceRoots(c comments.Comments, o types.Object, defaults []string) ([]string, error) {
+	n, ok := o.Type().(*types.Named)
+	if !ok {
+		return nil, nil
+	}
+	has := func(root string) bool {
+		rt, _ := xptypes.Field(n, root)
+		return rt != nil
+	}
+	roots := make([]string, 0)
+	for _, v := range markerValues(c, o, ReferenceRootsMarker) {
+		for root := range strings.SplitSeq(v, ",") {
+			if !has(root) {
+				return nil, diagnostic.Errorf(c.MarkerPosition(o, ReferenceRootsMarker), "invalid %s marker: type %s has no struct field %s", ReferenceRootsMarker, o.Name(), root)
+			}
+			roots = append(roots, root)
+		}
+	}
+	if len(roots) > 0 {
+		return roots, nil
+	}
+	for _, root := range defaults {
+		if has(root) {
+			roots = append(roots, root)
+		}
+	}
+	return roots, nil
+}
+
+// when returns a New that only writes for objects the supplied matcher
+// matches, typically one that checks for an opt-in marker.
+func when(m match.Matcher, fn method.New) method.New {
+	return func(f *jen.File, o types.Object) error {
+		if !m.Match(o) {
+			return nil
+		}
+		return fn(f, o)
+	}
+}
+
+// resolving returns a New that writes the ResolveReferences method of each
+// object, configured by the object's markers. References are resolved in the
+// supplied default roots of objects that don't specify their own.
+func resolving(c comments.Comments, defaultRoots []string, fn func(t *xptypes.Traverser, o ...method.ResolveReferencesOption) method.New) method.New {
+	return func(f *jen.File, o types.Object) error {
+		topts := make([]xptypes.TraverserOption, 0, 1)
+		for _, v := range markerValues(c, o, ReferenceMaxDepthMarker) {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return errors.Wrapf(err, "invalid %s marker", ReferenceMaxDepthMarker)
+			}
+			topts = append(topts, xptypes.WithMaxDepth(n))
+		}
+		ropts := make([]method.ResolveReferencesOption, 0, 3)
+		if slices.Contains(markerValues(c, o, ReferenceHelpersMarker), "true") {
+			ropts = append(ropts, method.WithTypeHelpers())
+		}
+		roots, err := referenceRoots(c, o, defaultRoots)
+		if err != nil {
+			return err
+		}
+		if len(roots) > 0 {
+			ropts = append(ropts, method.WithRoots(roots...))
+		}
+		if slices.Contains(markerValues(c, o, ReferenceOutsideRootsMarker), "reject") {
+			ropts = append(ropts, method.WithRejectOutsideRoots())
+		}
+		return fn(xptypes.NewTraverser(c, topts...), ropts...)(f, o)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package angryjet
+
+import (
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/crossplane/crossplane-tools/internal/comments"
+	"github.com/crossplane/crossplane-tools/internal/diagnostic"
+	"github.com/crossplane/crossplane-tools/internal/match"
+)
+
+// nearMisses returns a warning for each type that no generator matches, but
+// that nearly matches a generator that runs. Types that declare their kind,
+// and types the supplied filter excludes, are never near misses. Only one
+// warning is returned per type and kind of resource.
+func nearMisses(p *packages.Package, c comments.Comments, d declarations, include match.Matcher, opts *options) Errors {
+	warnings := Errors{}
+	disabled := match.HasMarker(c, DisableMarker, "false")
+	gs := opts.registered()
+	for _, n := range p.Types.Scope().Names() {
+		o, ok := p.Types.Scope().Lookup(n).(*types.TypeName)
+		if _, declared := d[o]; !ok || declared || disabled(o) || !include.Match(o) {
+			continue
+		}
+		// A type that any generator matches, even one that doesn't run,
+		// isn't a near miss of one of its other flavors.
+		if slices.ContainsFunc(gs, func(g Generator) bool { return g.Matcher.Match(o) }) {
+			continue
+		}
+		kinds := map[string]bool{}
+		for _, g := range gs {
+			nm, ok := g.Matcher.(match.NearMisser)
+			if !ok || !opts.runs(g) || kinds[g.Kind] {
+				continue
+			}
+			missing, ok := nm.NearMiss(o)
+			if !ok {
+				continue
+			}
+			kinds[g.Kind] = true
+			warnings = append(warnings, diagnostic.Errorf(p.Fset.Position(o.Pos()), "type %s nearly matches generator %s, but is missing %s", n, g.Name, missing))
+		}
+	}
+	return warnings
+}